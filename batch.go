@@ -0,0 +1,98 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	v1 "github.com/secberus/go-push-api/types/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// batcher buffers records for a single table and upserts them whenever the
+// buffer reaches maxRecords records or maxBytes encoded bytes. If replace is
+// set, records are held until flush, which deletes the records replace
+// matches before upserting them, so that rows are only ever replaced by a
+// complete set.
+type batcher struct {
+	sink       Sink
+	table      string
+	maxRecords int
+	maxBytes   int
+	replace    *v1.Record
+
+	recs    []*v1.Record
+	size    int
+	batches int
+	total   int
+}
+
+func (b *batcher) add(ctx context.Context, r *v1.Record) error {
+	n := proto.Size(r)
+	if b.replace == nil && len(b.recs) > 0 && (len(b.recs) >= b.maxRecords || b.size+n > b.maxBytes) {
+		if err := b.flush(ctx); err != nil {
+			return err
+		}
+	}
+	b.recs = append(b.recs, r)
+	b.size += n
+	return nil
+}
+
+func (b *batcher) flush(ctx context.Context) error {
+	// an upsert that has started is allowed to finish even if the run is
+	// cancelled, so that shutdown does not drop collected records
+	ctx = context.WithoutCancel(ctx)
+
+	if b.replace != nil {
+		if ds, ok := b.sink.(deleteSink); ok {
+			log.Printf("deleting records to be replaced for table %q", b.table)
+			if err := ds.Delete(ctx, []*v1.Record{b.replace}); err != nil {
+				return fmt.Errorf("failed to delete replaced records: %w", err)
+			}
+		} else {
+			log.Printf("sink cannot delete records, so replaced records of table %q may linger", b.table)
+		}
+		b.replace = nil
+
+		// the held records are upserted in batches as usual
+		held := b.recs
+		b.recs, b.size = nil, 0
+		for _, r := range held {
+			if err := b.add(ctx, r); err != nil {
+				return err
+			}
+		}
+	}
+	if len(b.recs) == 0 {
+		return nil
+	}
+
+	b.batches++
+	log.Printf("upserting batch %d of %d records (%d bytes) for table %q", b.batches, len(b.recs), b.size, b.table)
+	if err := b.sink.Upsert(ctx, b.recs); err != nil {
+		return fmt.Errorf("failed to upsert batch %d of %d records: %w", b.batches, len(b.recs), err)
+	}
+
+	b.total += len(b.recs)
+	b.recs = b.recs[:0]
+	b.size = 0
+	return nil
+}
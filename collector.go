@@ -19,83 +19,255 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"strings"
+	"maps"
+	"sync"
+	"time"
 
 	meraki "github.com/meraki/dashboard-api-go/v4/sdk"
-	api "github.com/secberus/go-push-api/api/v1"
-	service "github.com/secberus/go-push-api/service/v1/push"
 	v1 "github.com/secberus/go-push-api/types/v1"
 
+	"github.com/secberus/meraki-collector/config"
 	"github.com/secberus/meraki-collector/resource"
+	"github.com/secberus/meraki-collector/state"
 )
 
 type Collector struct {
+	mu      sync.Mutex
 	tables  map[string]struct{}
 	meraki  *meraki.Client
-	pushsvc service.PushServiceClient
+	sink    Sink
+	state   state.Store
+	workers semaphore
+	levels  []semaphore
+
+	batchSize   int
+	batchBytes  int
+	maxFailures int
 }
 
-func NewCollector(meraki *meraki.Client, pushsvc service.PushServiceClient) *Collector {
+func NewCollector(meraki *meraki.Client, sink Sink, state state.Store, cfg *config.CollectorConfig) *Collector {
+	levels := make([]semaphore, len(cfg.LevelWorkers))
+	for i, n := range cfg.LevelWorkers {
+		levels[i] = newSemaphore(n)
+	}
 	return &Collector{
 		tables:  make(map[string]struct{}),
 		meraki:  meraki,
-		pushsvc: pushsvc,
+		sink:    sink,
+		state:   state,
+		workers: newSemaphore(cfg.Workers),
+		levels:  levels,
+
+		batchSize:   cfg.BatchSize,
+		batchBytes:  cfg.BatchBytes,
+		maxFailures: cfg.MaxFailures,
 	}
 }
 
-func (c Collector) register(ctx context.Context, t *v1.Table) error {
+func (c *Collector) register(ctx context.Context, t *v1.Table) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if _, ok := c.tables[t.Name]; ok {
 		return nil
 	}
 
-	if _, err := c.pushsvc.GetTable(ctx, &api.GetTableInput{TableName: t.Name}); err != nil && strings.Contains(err.Error(), "not found") {
-		log.Printf("table %q does not exist, creating\n", t.Name)
-		if _, err := c.pushsvc.CreateTable(ctx, &api.CreateTableInput{Table: t}); err != nil {
-			return fmt.Errorf("failed to CreateTable: %w", err)
-		}
-	} else if err != nil {
-		return fmt.Errorf("failed to GetTable: %w", err)
+	if err := c.sink.Register(ctx, t); err != nil {
+		return err
 	}
 
 	c.tables[t.Name] = struct{}{}
 	return nil
 }
 
-func (c Collector) collect(ctx context.Context, rc *resource.Resource, parent any) error {
-	log.Printf("collecting for table %q", rc.Table.Name)
+// acquire takes a worker slot for the given tree depth, first from the
+// level's pool and then from the global pool.
+func (c *Collector) acquire(ctx context.Context, depth int) (func(), error) {
+	var level semaphore
+	if depth < len(c.levels) {
+		level = c.levels[depth]
+	}
+	if err := level.acquire(ctx); err != nil {
+		return nil, err
+	}
+	if err := c.workers.acquire(ctx); err != nil {
+		level.release()
+		return nil, err
+	}
+	return func() {
+		c.workers.release()
+		level.release()
+	}, nil
+}
+
+// run holds the state of a single collection. A run with a target resolves
+// the target's ancestors only to obtain parents, without upserting them, and
+// does not descend below the target.
+type run struct {
+	failures *failures
+	target   *resource.Resource
+	path     map[*resource.Resource]bool
+}
+
+func (r *run) emits(rc *resource.Resource) bool {
+	return r.target == nil || rc == r.target
+}
+
+func (r *run) children(rc *resource.Resource) []*resource.Resource {
+	if r.target == nil {
+		return rc.Children
+	}
+	var children []*resource.Resource
+	for _, cr := range rc.Children {
+		if r.path[cr] {
+			children = append(children, cr)
+		}
+	}
+	return children
+}
+
+func (c *Collector) collect(ctx context.Context, r *run, rc *resource.Resource, parent any, keys map[string]string, depth int) error {
+	release, err := c.acquire(ctx, depth)
+	if err != nil {
+		return err
+	}
+	r.failures.branch()
+
+	g, gctx := newGroup(ctx)
+	err = c.resolve(gctx, g, r, rc, parent, keys, depth)
+	release()
+
+	// a failed branch is recorded and skipped so that its siblings carry on,
+	// unless the run itself is being aborted
+	if err != nil && gctx.Err() == nil {
+		log.Printf("skipping failed branch: %s", err)
+		err = r.failures.record(Failure{Table: rc.Table.Name, Parent: parentKey(parent), Err: err}, depth == 0)
+	}
+	if err != nil {
+		g.cancel(err)
+	}
+
+	// children are waited on outside of the worker slot so that a parent
+	// never holds a slot its descendants need
+	if werr := g.Wait(); werr != nil {
+		err = werr
+	}
+	return err
+}
 
+// resolve collects the items of rc below parent. keys holds the values of
+// the parent key columns of every ancestor.
+func (c *Collector) resolve(ctx context.Context, g *group, r *run, rc *resource.Resource, parent any, keys map[string]string, depth int) error {
 	t := rc.Table
-	if err := c.register(ctx, t); err != nil {
-		return fmt.Errorf("failed to register table %q: %w", t.Name, err)
+	emit := r.emits(rc)
+	children := r.children(rc)
+
+	var b *batcher
+	if emit {
+		log.Printf("collecting for table %q", t.Name)
+		if err := c.register(ctx, t); err != nil {
+			return fmt.Errorf("failed to register table %q: %w", t.Name, err)
+		}
+		b = &batcher{
+			sink:       c.sink,
+			table:      t.Name,
+			maxRecords: c.batchSize,
+			maxBytes:   c.batchBytes,
+		}
+		if rc.Replace {
+			rec, err := rc.ParentKey(keys)
+			if err != nil {
+				return err
+			}
+			b.replace = rec
+		}
 	}
 
-	var recs []*v1.Record
-	for v, err := range rc.Resolver(ctx, c.meraki, parent) {
+	start := time.Now()
+	sc, err := state.NewScope(ctx, c.state, state.Key{Table: t.Name, Parent: parentKey(parent)})
+	if err != nil {
+		return err
+	}
+	for v, err := range rc.Resolver(resource.WithState(ctx, sc), c.meraki, parent) {
 		if err != nil {
 			return fmt.Errorf("failed to collect for table %q: %w", t.Name, err)
 		}
-		if r, err := resource.RecordFor(t, v); err != nil {
-			return fmt.Errorf("failed to create Record for table %q: %w", t.Name, err)
-		} else {
-			recs = append(recs, r)
+		// stop resolving once the run is cancelled, but still flush what
+		// has been collected so far
+		if ctx.Err() != nil {
+			break
 		}
-		for _, cr := range rc.Children {
-			if err := c.collect(ctx, cr, v); err != nil {
-				return fmt.Errorf("failed to collect child for table %q: %w", t.Name, err)
+		if emit {
+			rec, err := rc.Record(v, keys)
+			if es, ok := c.sink.(encodeErrorSink); ok && err != nil {
+				es.EncodeError(t, err)
+			} else if err != nil {
+				return fmt.Errorf("failed to create Record for table %q: %w", t.Name, err)
+			} else if err := b.add(ctx, rec); err != nil {
+				return fmt.Errorf("failed to upsert records for table %q: %w", t.Name, err)
 			}
 		}
+		ckeys := keys
+		if rc.KeyColumn != "" && len(children) > 0 {
+			ckeys = maps.Clone(keys)
+			ckeys[rc.KeyColumn] = rc.KeyValue(v)
+		}
+		for _, cr := range children {
+			g.Go(func() error {
+				return c.collect(ctx, r, cr, v, ckeys, depth+1)
+			})
+		}
 	}
 
-	log.Printf("upserting %d records for table %q", len(recs), t.Name)
-	if _, err := c.pushsvc.UpsertRecords(ctx, &api.UpsertRecordsInput{Records: recs}); err != nil {
-		return fmt.Errorf("failed to upsert %d records: %w", len(recs), err)
+	if emit {
+		// the rows of a parent are only replaced by a complete set of items
+		if ctx.Err() != nil && b.replace != nil {
+			log.Printf("discarding %d records of an incomplete replacement for table %q", len(b.recs), t.Name)
+			b.replace, b.recs = nil, nil
+		}
+		if err := b.flush(ctx); err != nil {
+			return fmt.Errorf("failed to upsert records for table %q: %w", t.Name, err)
+		}
+		log.Printf("upserted %d records in %d batches for table %q", b.total, b.batches, t.Name)
+
+		// state is only saved once the records it describes are upserted,
+		// and only if the resolver ran to completion: a cursor set by a
+		// partially read resolver may skip items that were never fetched
+		if ctx.Err() == nil {
+			if err := sc.Commit(ctx, start); err != nil {
+				return err
+			}
+		}
 	}
 
-	return nil
+	return context.Cause(ctx)
+}
+
+func (c *Collector) run(ctx context.Context, r *run, root *resource.Resource) error {
+	err := c.collect(ctx, r, root, nil, map[string]string{}, 0)
+
+	if s := r.failures.summary(); s != nil {
+		if s.Failed() {
+			return errors.Join(s, err)
+		}
+		log.Printf("collection completed with failures: %s", s)
+	}
+	for table, n := range resource.CoercionFailures() {
+		log.Printf("%d values of table %q have failed coercion and been kept as text in their _raw column since startup", n, table)
+	}
+	return err
 }
 
-func (c Collector) Collect(ctx context.Context, rc *resource.Resource) error {
-	return c.collect(ctx, rc, nil)
+// Collect walks the resource tree rooted at rc. Branches that fail are
+// skipped; the returned error is a *CollectError if rc or every branch
+// failed, or if more than maxFailures branches failed, joined with the cause
+// if the run was aborted.
+func (c *Collector) Collect(ctx context.Context, rc *resource.Resource) error {
+	if err := resource.Validate(rc); err != nil {
+		return fmt.Errorf("invalid resource tree: %w", err)
+	}
+	return c.run(ctx, &run{failures: newFailures(c.maxFailures)}, rc)
 }
@@ -0,0 +1,125 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"strconv"
+	"testing"
+
+	meraki "github.com/meraki/dashboard-api-go/v4/sdk"
+	v1 "github.com/secberus/go-push-api/types/v1"
+
+	"github.com/secberus/meraki-collector/config"
+	"github.com/secberus/meraki-collector/resource"
+	"github.com/secberus/meraki-collector/state"
+)
+
+type cursorItem struct {
+	ID string `s6s:"id,pk"`
+}
+
+// cursorResource yields n items, advancing the cursor to each, and calls
+// after with the number of items yielded so far.
+func cursorResource(n int, after func(int)) *resource.Resource {
+	return (&resource.Of[any, cursorItem]{
+		Table: &v1.Table{
+			Name:     "test_cursor",
+			SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
+		},
+		Resolver: func(ctx context.Context, _ *meraki.Client, _ any) iter.Seq2[cursorItem, error] {
+			s := resource.StateFrom(ctx)
+			return func(yield func(cursorItem, error) bool) {
+				for i := 1; i <= n; i++ {
+					id := strconv.Itoa(i)
+					s.SetCursor(id)
+					if !yield(cursorItem{ID: id}, nil) {
+						return
+					}
+					after(i)
+				}
+			}
+		},
+	}).Resource()
+}
+
+func TestCollectCursor(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		cancelAt int
+		cursor   string
+	}{
+		{"complete", 0, "3"},
+		{"cancelled", 2, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			rc := cursorResource(3, func(i int) {
+				if i == tc.cancelAt {
+					cancel()
+				}
+			})
+			store := state.NewMemory()
+			c := NewCollector(nil, newDryRunSink(), store, &config.CollectorConfig{
+				BatchSize:   10,
+				BatchBytes:  1 << 20,
+				MaxFailures: -1,
+			})
+			err := c.Collect(ctx, rc)
+			if tc.cancelAt == 0 && err != nil {
+				t.Fatalf("Collect: %v", err)
+			}
+
+			e, _, err := store.Load(context.Background(), state.Key{Table: rc.Table.Name})
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if e.Cursor != tc.cursor {
+				t.Errorf("cursor = %q, want %q", e.Cursor, tc.cursor)
+			}
+		})
+	}
+}
+
+func TestCollectRootFailure(t *testing.T) {
+	rc := (&resource.Of[any, cursorItem]{
+		Table: &v1.Table{
+			Name:     "test_root_failure",
+			SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
+		},
+		Resolver: func(context.Context, *meraki.Client, any) iter.Seq2[cursorItem, error] {
+			return func(yield func(cursorItem, error) bool) {
+				yield(cursorItem{}, errors.New("401 Unauthorized"))
+			}
+		},
+	}).Resource()
+
+	c := NewCollector(nil, newDryRunSink(), state.NewMemory(), &config.CollectorConfig{
+		BatchSize:   10,
+		BatchBytes:  1 << 20,
+		MaxFailures: -1,
+	})
+	var ce *CollectError
+	if err := c.Collect(context.Background(), rc); !errors.As(err, &ce) || !ce.RootFailed {
+		t.Errorf("Collect = %v, want a *CollectError for the root", err)
+	}
+}
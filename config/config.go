@@ -18,15 +18,33 @@ package config
 
 import (
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 const (
-	DefaultConfigFile = "$HOME/.s6s/config"
-	ConfigFileEnvVar  = "S6S_CONFIG_FILE"
-	DefaultEndpoint   = "push.secberus.io:7744"
-	DefaultBaseUrl    = "https://api.meraki.com/"
+	DefaultConfigFile   = "$HOME/.s6s/config"
+	DefaultStateDir     = "$HOME/.s6s/state"
+	ConfigFileEnvVar    = "S6S_CONFIG_FILE"
+	DefaultEndpoint     = "push.secberus.io:7744"
+	DefaultBaseUrl      = "https://api.meraki.com/"
+	DefaultSchemaPolicy = "refuse"
+	DefaultWorkers      = 10
+	DefaultBatchSize    = 1000
+	DefaultBatchBytes   = 3 << 20
+
+	// DefaultMaxFailures never aborts a run early, however many branches
+	// fail. A non-negative max_failures aborts a run once more branches than
+	// that have failed, so 0 aborts on the first failure. A run in which the
+	// root or every branch failed is reported as failed either way.
+	DefaultMaxFailures = -1
+
+	DefaultRequestsPerSecond = 10
+	DefaultMaxRetries        = 5
+
+	DefaultInterval = time.Hour
+	DefaultJitter   = time.Minute
 )
 
 type S6sConfig struct {
@@ -34,17 +52,51 @@ type S6sConfig struct {
 	X509Certificate string `yaml:"x509_certificate"`
 	PrivateKey      string `yaml:"private_key"`
 	CABundle        string `yaml:"ca_bundle"`
+	SchemaPolicy    string `yaml:"schema_policy"`
 }
 
 type MerakiConfig struct {
-	BaseUrl string `yaml:"base_url"`
-	ApiKey  string `yaml:"api_key"`
-	Debug   bool   `yaml:"debug"`
+	BaseUrl           string `yaml:"base_url"`
+	ApiKey            string `yaml:"api_key"`
+	Debug             bool   `yaml:"debug"`
+	RequestsPerSecond int    `yaml:"requests_per_second"`
+	MaxRetries        int    `yaml:"max_retries"`
+}
+
+type CollectorConfig struct {
+	Workers      int   `yaml:"workers"`
+	LevelWorkers []int `yaml:"level_workers"`
+	BatchSize    int   `yaml:"batch_size"`
+	BatchBytes   int   `yaml:"batch_bytes"`
+	MaxFailures  int   `yaml:"max_failures"`
+	// FlattenDepth maps table names to the depth to which nested structs
+	// are expanded into prefixed columns instead of jsonb columns.
+	FlattenDepth map[string]int `yaml:"flatten_depth"`
+}
+
+type ServeConfig struct {
+	Interval  time.Duration            `yaml:"interval"`
+	Jitter    time.Duration            `yaml:"jitter"`
+	Schedules map[string]time.Duration `yaml:"schedules"`
+}
+
+type SinkConfig struct {
+	Type string `yaml:"type"`
+	Path string `yaml:"path"`
+}
+
+type StateConfig struct {
+	Driver string `yaml:"driver"`
+	Path   string `yaml:"path"`
 }
 
 type Config struct {
-	S6s    S6sConfig    `yaml:"s6s"`
-	Meraki MerakiConfig `yaml:"meraki"`
+	S6s       S6sConfig       `yaml:"s6s"`
+	Meraki    MerakiConfig    `yaml:"meraki"`
+	Collector CollectorConfig `yaml:"collector"`
+	Serve     ServeConfig     `yaml:"serve"`
+	State     StateConfig     `yaml:"state"`
+	Sink      SinkConfig      `yaml:"sink"`
 }
 
 func Load() (*Config, error) {
@@ -62,7 +114,17 @@ func Load() (*Config, error) {
 
 	cfg := new(Config)
 	cfg.S6s.Endpoint = DefaultEndpoint
+	cfg.S6s.SchemaPolicy = DefaultSchemaPolicy
 	cfg.Meraki.BaseUrl = DefaultBaseUrl
+	cfg.Meraki.RequestsPerSecond = DefaultRequestsPerSecond
+	cfg.Meraki.MaxRetries = DefaultMaxRetries
+	cfg.Collector.Workers = DefaultWorkers
+	cfg.Collector.BatchSize = DefaultBatchSize
+	cfg.Collector.BatchBytes = DefaultBatchBytes
+	cfg.Collector.MaxFailures = DefaultMaxFailures
+	cfg.Serve.Interval = DefaultInterval
+	cfg.Serve.Jitter = DefaultJitter
+	cfg.State.Path = DefaultStateDir
 
 	if err := yaml.Unmarshal(raw, &cfg); err != nil {
 		return nil, err
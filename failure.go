@@ -0,0 +1,135 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var ErrTooManyFailures = errors.New("too many failures")
+
+// Failure is a single resource branch that could not be collected.
+type Failure struct {
+	Table  string
+	Parent string
+	Err    error
+}
+
+func (f Failure) String() string {
+	if f.Parent == "" {
+		return fmt.Sprintf("%s: %s", f.Table, f.Err)
+	}
+	return fmt.Sprintf("%s [%s]: %s", f.Table, f.Parent, f.Err)
+}
+
+// CollectError summarizes the branches that failed during a run.
+type CollectError struct {
+	Branches    int
+	MaxFailures int
+	Failures    []Failure
+	// RootFailed is set if the root resource itself failed.
+	RootFailed bool
+}
+
+func (e *CollectError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d of %d branches failed (max_failures %d)", len(e.Failures), e.Branches, e.MaxFailures)
+	for _, f := range e.Failures {
+		b.WriteString("\n  ")
+		b.WriteString(f.String())
+	}
+	return b.String()
+}
+
+func (e *CollectError) Exceeded() bool {
+	return e.MaxFailures >= 0 && len(e.Failures) > e.MaxFailures
+}
+
+// Failed reports whether the run failed as a whole, which is the case if
+// the root resource or every branch failed, whatever MaxFailures is, or if
+// more than MaxFailures branches failed.
+func (e *CollectError) Failed() bool {
+	return e.RootFailed || len(e.Failures) >= e.Branches || e.Exceeded()
+}
+
+// failures records branch failures for a single run. A negative max never
+// aborts the run.
+type failures struct {
+	mu  sync.Mutex
+	err CollectError
+}
+
+func newFailures(max int) *failures {
+	return &failures{err: CollectError{MaxFailures: max}}
+}
+
+func (fs *failures) branch() {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.err.Branches++
+}
+
+// record adds a failure, of the root resource if root is set, and returns
+// ErrTooManyFailures once the threshold is exceeded.
+func (fs *failures) record(f Failure, root bool) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.err.Failures = append(fs.err.Failures, f)
+	fs.err.RootFailed = fs.err.RootFailed || root
+	if fs.err.Exceeded() {
+		return ErrTooManyFailures
+	}
+	return nil
+}
+
+func (fs *failures) summary() *CollectError {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if len(fs.err.Failures) == 0 {
+		return nil
+	}
+	return &CollectError{
+		Branches:    fs.err.Branches,
+		MaxFailures: fs.err.MaxFailures,
+		Failures:    append([]Failure(nil), fs.err.Failures...),
+		RootFailed:  fs.err.RootFailed,
+	}
+}
+
+// parentKey describes a parent value by its identifying field for failure reports.
+func parentKey(v any) string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return ""
+	}
+	for _, k := range [][2]string{{"Serial", "serial"}, {"ID", "id"}, {"NetworkID", "network_id"}, {"NetworkId", "network_id"}} {
+		if f := rv.FieldByName(k[0]); f.IsValid() && f.Kind() == reflect.String && f.String() != "" {
+			return k[1] + " " + f.String()
+		}
+	}
+	return ""
+}
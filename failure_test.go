@@ -0,0 +1,94 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFailuresThreshold(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		max  int
+		// abortAt is the failure that returns ErrTooManyFailures, or 0
+		abortAt int
+	}{
+		{"unlimited", -1, 0},
+		{"zero", 0, 1},
+		{"two", 2, 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := newFailures(tc.max)
+			for i := 1; i <= 5; i++ {
+				fs.branch()
+				err := fs.record(Failure{Table: "t", Err: errors.New("failed")}, false)
+				if abort := tc.abortAt > 0 && i >= tc.abortAt; abort != errors.Is(err, ErrTooManyFailures) {
+					t.Errorf("failure %d: record = %v, want abort %t", i, err, abort)
+				}
+			}
+		})
+	}
+}
+
+func TestFailuresSummary(t *testing.T) {
+	fail := Failure{Table: "t", Parent: "id 1", Err: errors.New("failed")}
+
+	fs := newFailures(-1)
+	fs.branch()
+	if s := fs.summary(); s != nil {
+		t.Fatalf("summary without failures = %v, want nil", s)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		branches int
+		failures int
+		root     bool
+		max      int
+		failed   bool
+	}{
+		{name: "some branches", branches: 3, failures: 1, max: -1},
+		{name: "every branch", branches: 3, failures: 3, max: -1, failed: true},
+		{name: "root", branches: 3, failures: 1, root: true, max: -1, failed: true},
+		{name: "under threshold", branches: 4, failures: 2, max: 2},
+		{name: "over threshold", branches: 4, failures: 3, max: 2, failed: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := newFailures(tc.max)
+			for range tc.branches {
+				fs.branch()
+			}
+			for i := range tc.failures {
+				fs.record(fail, tc.root && i == 0)
+			}
+
+			s := fs.summary()
+			if s == nil {
+				t.Fatal("summary = nil")
+			}
+			if s.Branches != tc.branches || len(s.Failures) != tc.failures || s.RootFailed != tc.root {
+				t.Errorf("summary = %d of %d branches, root %t, want %d of %d, root %t",
+					len(s.Failures), s.Branches, s.RootFailed, tc.failures, tc.branches, tc.root)
+			}
+			if s.Failed() != tc.failed {
+				t.Errorf("Failed = %t, want %t", s.Failed(), tc.failed)
+			}
+		})
+	}
+}
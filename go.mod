@@ -3,6 +3,8 @@ module github.com/secberus/meraki-collector
 go 1.24.0
 
 require (
+	github.com/go-resty/resty/v2 v2.11.0
+	github.com/juju/ratelimit v1.0.2
 	github.com/meraki/dashboard-api-go/v4 v4.0.6
 	github.com/secberus/go-push-api v0.0.0-20250224173800-aad67da679bb
 	google.golang.org/grpc v1.70.0
@@ -12,9 +14,7 @@ require (
 
 require (
 	buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go v1.36.5-20250219170025-d39267d9df8f.1 // indirect
-	github.com/go-resty/resty/v2 v2.11.0 // indirect
 	github.com/google/go-querystring v1.1.0 // indirect
-	github.com/juju/ratelimit v1.0.2 // indirect
 	golang.org/x/net v0.32.0 // indirect
 	golang.org/x/sys v0.28.0 // indirect
 	golang.org/x/text v0.21.0 // indirect
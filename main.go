@@ -19,32 +19,87 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/secberus/meraki-collector/config"
 	"github.com/secberus/meraki-collector/resource"
+	"github.com/secberus/meraki-collector/state"
 )
 
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] [collect|serve]\n", os.Args[0])
+	flag.PrintDefaults()
+}
+
 func main() {
+	dryRun := flag.Bool("dry-run", false, "resolve and encode every record without registering or upserting anything")
+	flag.Usage = usage
+	flag.Parse()
+
+	cmd := "collect"
+	if flag.NArg() > 0 {
+		cmd = flag.Arg(0)
+	}
+	if flag.NArg() > 1 || (cmd != "collect" && cmd != "serve") || (*dryRun && cmd != "collect") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("failed to load configuration: %s", err)
 	}
 
-	meraki, err := initMerakiClient(&cfg.Meraki)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	meraki, err := initMerakiClient(ctx, &cfg.Meraki)
 	if err != nil {
 		log.Fatalf("failed to initialize Meraki client: %s", err)
 	}
 
-	pushsvc, err := initPushClient(&cfg.S6s)
+	store, err := state.Open(cfg.State.Driver, os.ExpandEnv(cfg.State.Path))
 	if err != nil {
-		log.Fatalf("failed to initialize Push client: %s", err)
+		log.Fatalf("failed to open state store: %s", err)
+	}
+
+	var sink Sink
+	if *dryRun {
+		// a dry run must not advance any checkpoints either
+		sink, store = newDryRunSink(), state.ReadOnly(store)
+	} else if sink, err = initSink(cfg, store); err != nil {
+		log.Fatalf("failed to initialize sink: %s", err)
 	}
+	defer sink.Close()
+	defer store.Close()
 
-	collector := NewCollector(meraki, pushsvc)
+	collector := NewCollector(meraki, sink, store, &cfg.Collector)
+
+	for table, depth := range cfg.Collector.FlattenDepth {
+		if err := resource.SetFlattenDepth(table, depth); err != nil {
+			log.Fatalf("invalid collector configuration: %s", err)
+		}
+	}
+	root := resource.Organizations.Resource()
 
-	// collect from Meraki API root (organizations)
-	if err := collector.Collect(context.Background(), resource.Organizations); err != nil {
-		log.Fatalf("failed to collect from Meraki API: %s", err)
+	switch cmd {
+	case "serve":
+		if err := collector.Serve(ctx, root, &cfg.Serve); err != nil {
+			log.Fatalf("failed to serve: %s", err)
+		}
+	default:
+		// collect from Meraki API root (organizations)
+		err := collector.Collect(ctx, root)
+		if ds, ok := sink.(*dryRunSink); ok {
+			ds.Report(os.Stdout)
+		}
+		if err != nil {
+			log.Fatalf("failed to collect from Meraki API: %s", err)
+		}
 	}
 }
@@ -18,14 +18,28 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"strconv"
+	"time"
 
+	"github.com/go-resty/resty/v2"
 	meraki "github.com/meraki/dashboard-api-go/v4/sdk"
+
 	"github.com/secberus/meraki-collector/config"
 )
 
-func initMerakiClient(cfg *config.MerakiConfig) (*meraki.Client, error) {
+// ipRequestsPerSecond is the Meraki API limit per source IP, applied across
+// all organizations on top of the per-organization buckets.
+const ipRequestsPerSecond = 100
+
+// initMerakiClient returns a client whose requests, including the waits
+// between retries, are cancelled with ctx, which the SDK does not take.
+func initMerakiClient(ctx context.Context, cfg *config.MerakiConfig) (*meraki.Client, error) {
+	if cfg.RequestsPerSecond <= 0 {
+		return nil, fmt.Errorf("invalid requests_per_second %d: must be positive", cfg.RequestsPerSecond)
+	}
+
 	client, err := meraki.NewClientWithOptions(
 		cfg.BaseUrl,
 		cfg.ApiKey,
@@ -35,6 +49,21 @@ func initMerakiClient(cfg *config.MerakiConfig) (*meraki.Client, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Meraki client: %w", err)
 	}
+	client.SetRequestsPerSecond(ipRequestsPerSecond)
+
+	limiter := newOrgLimiter(cfg.RequestsPerSecond)
+	client.RestyClient().
+		OnBeforeRequest(func(_ *resty.Client, r *resty.Request) error {
+			r.SetContext(ctx)
+			return nil
+		}).
+		OnBeforeRequest(limiter.beforeRequest).
+		OnAfterResponse(limiter.afterResponse).
+		SetRetryCount(cfg.MaxRetries).
+		SetRetryWaitTime(time.Second).
+		SetRetryMaxWaitTime(time.Minute).
+		SetRetryAfter(retryAfter).
+		AddRetryCondition(retryable)
 
 	return client, nil
 }
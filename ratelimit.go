@@ -0,0 +1,177 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/juju/ratelimit"
+)
+
+// orgLimiter schedules Meraki API requests through one token bucket per
+// organization. Requests against networks and devices are attributed to the
+// organization that owns them, learned from earlier list responses; requests
+// that cannot be attributed share a single bucket.
+type orgLimiter struct {
+	mu      sync.Mutex
+	rate    int64
+	buckets map[string]*ratelimit.Bucket
+	owners  map[string]string
+}
+
+func newOrgLimiter(rps int) *orgLimiter {
+	return &orgLimiter{
+		rate:    int64(rps),
+		buckets: make(map[string]*ratelimit.Bucket),
+		owners:  make(map[string]string),
+	}
+}
+
+func (l *orgLimiter) bucket(org string) *ratelimit.Bucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[org]
+	if !ok {
+		b = ratelimit.NewBucketWithQuantum(time.Second, l.rate, l.rate)
+		l.buckets[org] = b
+	}
+	return b
+}
+
+// orgFor returns the organization that owns the object addressed by path,
+// or "" if it is not known.
+func (l *orgLimiter) orgFor(path string) string {
+	kind, id := pathObject(path)
+	if kind == "organizations" {
+		return id
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.owners[kind+"/"+id]
+}
+
+func (l *orgLimiter) learn(org string, kind string, ids ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, id := range ids {
+		if id != "" {
+			l.owners[kind+"/"+id] = org
+		}
+	}
+}
+
+func (l *orgLimiter) beforeRequest(_ *resty.Client, r *resty.Request) error {
+	b := l.bucket(l.orgFor(r.URL))
+	if d := b.Take(1); d > 0 {
+		select {
+		case <-time.After(d):
+		case <-r.Context().Done():
+			return r.Context().Err()
+		}
+	}
+	return nil
+}
+
+// afterResponse records the networks and devices listed in a successful
+// response as belonging to the organization the request was made for.
+func (l *orgLimiter) afterResponse(_ *resty.Client, r *resty.Response) error {
+	if r.IsError() {
+		return nil
+	}
+	org := l.orgFor(r.Request.URL)
+	if org == "" {
+		return nil
+	}
+
+	var items []struct {
+		ID     string `json:"id"`
+		Serial string `json:"serial"`
+	}
+	if err := json.Unmarshal(r.Body(), &items); err != nil {
+		return nil
+	}
+
+	kind, _ := pathObject(r.Request.URL)
+	for _, i := range items {
+		if kind == "organizations" && strings.HasSuffix(urlPath(r.Request.URL), "/networks") {
+			l.learn(org, "networks", i.ID)
+		}
+		l.learn(org, "devices", i.Serial)
+	}
+	return nil
+}
+
+func urlPath(raw string) string {
+	if u, err := url.Parse(raw); err == nil {
+		return strings.TrimSuffix(u.Path, "/")
+	}
+	return raw
+}
+
+// pathObject returns the top-level object kind and ID addressed by a Meraki
+// API path, e.g. ("networks", "N_123") for /api/v1/networks/N_123/devices.
+func pathObject(raw string) (kind string, id string) {
+	segs := strings.Split(strings.Trim(urlPath(raw), "/"), "/")
+	for i := 0; i+1 < len(segs); i++ {
+		switch segs[i] {
+		case "organizations", "networks", "devices":
+			return segs[i], segs[i+1]
+		}
+	}
+	return "", ""
+}
+
+// retryable retries requests that failed in transport or were answered with
+// 429 or 5xx. Other errors, such as responses that fail to decode, would only
+// fail again, and cancelled requests are not retried either.
+func retryable(r *resty.Response, err error) bool {
+	if r != nil && r.RawResponse != nil {
+		return r.StatusCode() == http.StatusTooManyRequests || r.StatusCode() >= http.StatusInternalServerError
+	}
+	var uerr *url.Error
+	return errors.As(err, &uerr) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// retryAfter honours the Retry-After header in either of its forms; a zero
+// duration lets resty fall back to jittered exponential backoff.
+func retryAfter(_ *resty.Client, r *resty.Response) (time.Duration, error) {
+	h := r.Header().Get("Retry-After")
+	if h == "" {
+		return 0, nil
+	}
+	if s, err := strconv.Atoi(h); err == nil {
+		return time.Duration(s) * time.Second, nil
+	}
+	if t, err := http.ParseTime(h); err == nil {
+		return max(time.Until(t), 0), nil
+	}
+	log.Printf("ignoring malformed Retry-After header %q\n", h)
+	return 0, nil
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+func TestPathObject(t *testing.T) {
+	for _, tc := range []struct {
+		path, kind, id string
+	}{
+		{"/api/v1/organizations", "", ""},
+		{"/api/v1/organizations/O_1", "organizations", "O_1"},
+		{"/api/v1/organizations/O_1/networks?perPage=1000", "organizations", "O_1"},
+		{"https://api.meraki.com/api/v1/networks/N_1/devices", "networks", "N_1"},
+		{"/api/v1/devices/Q2XX-1234/clients/", "devices", "Q2XX-1234"},
+		{"/api/v1/networks/N_1/devices/Q2XX-1234", "networks", "N_1"},
+	} {
+		if kind, id := pathObject(tc.path); kind != tc.kind || id != tc.id {
+			t.Errorf("pathObject(%q) = %q, %q, want %q, %q", tc.path, kind, id, tc.kind, tc.id)
+		}
+	}
+}
+
+func response(status int, header http.Header) *resty.Response {
+	return &resty.Response{RawResponse: &http.Response{StatusCode: status, Header: header}}
+}
+
+func TestRetryAfter(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		value string
+		min   time.Duration
+		max   time.Duration
+	}{
+		{"missing", "", 0, 0},
+		{"seconds", "3", 3 * time.Second, 3 * time.Second},
+		{"date", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), 58 * time.Second, time.Minute},
+		{"past date", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 0, 0},
+		{"malformed", "soon", 0, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := http.Header{}
+			if tc.value != "" {
+				h.Set("Retry-After", tc.value)
+			}
+			d, err := retryAfter(nil, response(http.StatusTooManyRequests, h))
+			if err != nil || d < tc.min || d > tc.max {
+				t.Errorf("retryAfter = %s, %v, want between %s and %s", d, err, tc.min, tc.max)
+			}
+		})
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	transport := &url.Error{Op: "Get", URL: "https://api.meraki.com", Err: errors.New("connection reset by peer")}
+	for _, tc := range []struct {
+		name string
+		rsp  *resty.Response
+		err  error
+		want bool
+	}{
+		{"ok", response(http.StatusOK, nil), nil, false},
+		{"bad request", response(http.StatusBadRequest, nil), nil, false},
+		{"too many requests", response(http.StatusTooManyRequests, nil), nil, true},
+		{"server error", response(http.StatusBadGateway, nil), nil, true},
+		{"server error that fails to decode", response(http.StatusInternalServerError, nil), errors.New("invalid character"), true},
+		{"decode error", response(http.StatusOK, nil), errors.New("invalid character"), false},
+		{"transport error", &resty.Response{}, transport, true},
+		{"cancelled", &resty.Response{}, &url.Error{Op: "Get", URL: "https://api.meraki.com", Err: context.Canceled}, false},
+		{"other error", nil, errors.New("rate limit exceeded"), false},
+	} {
+		if got := retryable(tc.rsp, tc.err); got != tc.want {
+			t.Errorf("%s: retryable = %t, want %t", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestOrgAttribution(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/organizations/O_1/networks":
+			fmt.Fprint(w, `[{"id": "N_1"}, {"id": "N_2"}]`)
+		case "/api/v1/networks/N_1/devices":
+			fmt.Fprint(w, `[{"serial": "Q2XX-0001"}]`)
+		case "/api/v1/organizations/O_2/networks":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `[{"id": "N_3"}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	l := newOrgLimiter(100)
+	client := resty.New().SetBaseURL(srv.URL).OnAfterResponse(l.afterResponse)
+	for _, path := range []string{
+		"/api/v1/organizations/O_1/networks",
+		"/api/v1/networks/N_1/devices",
+		"/api/v1/organizations/O_2/networks",
+	} {
+		if _, err := client.R().Get(path); err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+	}
+
+	for _, tc := range []struct {
+		path, org string
+	}{
+		{"/api/v1/organizations/O_1/admins", "O_1"},
+		{"/api/v1/networks/N_2/appliance/firewall/l3FirewallRules", "O_1"},
+		{"/api/v1/devices/Q2XX-0001/switch/ports", "O_1"},
+		// error responses are not learned from
+		{"/api/v1/networks/N_3/devices", ""},
+		{"/api/v1/networks/N_9/devices", ""},
+	} {
+		if org := l.orgFor(srv.URL + tc.path); org != tc.org {
+			t.Errorf("orgFor(%q) = %q, want %q", tc.path, org, tc.org)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package resource
+
+import (
+	"github.com/go-resty/resty/v2"
+	meraki "github.com/meraki/dashboard-api-go/v4/sdk"
+)
+
+// the firewall and NAT rules of MX appliances, a row per rule
+
+var ApplianceL3FirewallRules = applianceRules("meraki_appliance_l3_firewall_rules", "GetNetworkApplianceFirewallL3FirewallRules",
+	(*meraki.ApplianceService).GetNetworkApplianceFirewallL3FirewallRules,
+	func(r *meraki.ResponseApplianceGetNetworkApplianceFirewallL3FirewallRules) *[]meraki.ResponseApplianceGetNetworkApplianceFirewallL3FirewallRulesRules {
+		return r.Rules
+	})
+
+var ApplianceL7FirewallRules = applianceRules("meraki_appliance_l7_firewall_rules", "GetNetworkApplianceFirewallL7FirewallRules",
+	(*meraki.ApplianceService).GetNetworkApplianceFirewallL7FirewallRules,
+	func(r *meraki.ResponseApplianceGetNetworkApplianceFirewallL7FirewallRules) *[]meraki.ResponseApplianceGetNetworkApplianceFirewallL7FirewallRulesRules {
+		return r.Rules
+	})
+
+var ApplianceInboundFirewallRules = applianceRules("meraki_appliance_inbound_firewall_rules", "GetNetworkApplianceFirewallInboundFirewallRules",
+	(*meraki.ApplianceService).GetNetworkApplianceFirewallInboundFirewallRules,
+	func(r *meraki.ResponseApplianceGetNetworkApplianceFirewallInboundFirewallRules) *[]meraki.ResponseApplianceGetNetworkApplianceFirewallInboundFirewallRulesRules {
+		return r.Rules
+	})
+
+var AppliancePortForwardingRules = applianceRules("meraki_appliance_port_forwarding_rules", "GetNetworkApplianceFirewallPortForwardingRules",
+	(*meraki.ApplianceService).GetNetworkApplianceFirewallPortForwardingRules,
+	func(r *meraki.ResponseApplianceGetNetworkApplianceFirewallPortForwardingRules) *[]meraki.ResponseApplianceGetNetworkApplianceFirewallPortForwardingRulesRules {
+		return r.Rules
+	})
+
+var ApplianceOneToOneNatRules = applianceRules("meraki_appliance_one_to_one_nat_rules", "GetNetworkApplianceFirewallOneToOneNatRules",
+	(*meraki.ApplianceService).GetNetworkApplianceFirewallOneToOneNatRules,
+	func(r *meraki.ResponseApplianceGetNetworkApplianceFirewallOneToOneNatRules) *[]meraki.ResponseApplianceGetNetworkApplianceFirewallOneToOneNatRulesRules {
+		return r.Rules
+	})
+
+var ApplianceOneToManyNatRules = applianceRules("meraki_appliance_one_to_many_nat_rules", "GetNetworkApplianceFirewallOneToManyNatRules",
+	(*meraki.ApplianceService).GetNetworkApplianceFirewallOneToManyNatRules,
+	func(r *meraki.ResponseApplianceGetNetworkApplianceFirewallOneToManyNatRules) *[]meraki.ResponseApplianceGetNetworkApplianceFirewallOneToManyNatRulesRules {
+		return r.Rules
+	})
+
+// applianceRules returns a child of Networks with a row per rule, skipping
+// networks without an MX appliance.
+func applianceRules[R, E any](table, op string, fetch func(*meraki.ApplianceService, string) (*R, *resty.Response, error), rules func(*R) *[]E) *Of[merakiNetwork, element[E]] {
+	return ruleList(table, op, lacksProductType("appliance"), func(client *meraki.Client, n merakiNetwork) (*R, *resty.Response, error) {
+		return fetch(client.Appliance, n.ID)
+	}, rules, "network_id")
+}
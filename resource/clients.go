@@ -19,46 +19,24 @@ package resource
 
 import (
 	"context"
-	"errors"
-	"fmt"
 	"iter"
-	"log"
 
+	"github.com/go-resty/resty/v2"
 	meraki "github.com/meraki/dashboard-api-go/v4/sdk"
 	v1 "github.com/secberus/go-push-api/types/v1"
 )
 
-var Clients = &Resource{
+var Clients = &Of[merakiDevice, meraki.ResponseItemDevicesGetDeviceClients]{
 	Table: &v1.Table{
 		Name:     "meraki_device_clients",
 		SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
-		Columns:  columnsFor[meraki.ResponseItemDevicesGetDeviceClients]("serial"),
 	},
-	Resolver: getDeviceClients,
+	PrimaryKey: []string{"device_serial", "id"},
+	Resolver:   getDeviceClients,
 }
 
-func getDeviceClients(_ context.Context, client *meraki.Client, device any) iter.Seq2[any, error] {
-	serial := device.(meraki.ResponseItemNetworksGetNetworkDevices).Serial
-	return func(yield func(any, error) bool) {
-		rsl, rsp, err := client.Devices.GetDeviceClients(serial, nil)
-		if err != nil {
-			if rsp != nil && rsp.IsError() {
-				log.Printf("rsp status: %s, error: %+v\n", rsp.Status(), rsp.Error())
-				if err2, ok := rsp.Error().(error); ok {
-					err = errors.Join(err, err2)
-				}
-			}
-			yield(nil, fmt.Errorf("failed to GetDeviceClients: %w", err))
-			return
-		}
-		if rsl == nil {
-			yield(nil, errors.New("received nil response from GetDeviceClients"))
-			return
-		}
-		for _, i := range *rsl {
-			if !yield(i, nil) {
-				return
-			}
-		}
-	}
+func getDeviceClients(_ context.Context, client *meraki.Client, device merakiDevice) iter.Seq2[meraki.ResponseItemDevicesGetDeviceClients, error] {
+	return list("GetDeviceClients", func() (*meraki.ResponseDevicesGetDeviceClients, *resty.Response, error) {
+		return client.Devices.GetDeviceClients(device.Serial, nil)
+	})
 }
@@ -19,50 +19,71 @@ package resource
 
 import (
 	"context"
-	"errors"
-	"fmt"
 	"iter"
-	"log"
 	"time"
 
+	"github.com/go-resty/resty/v2"
 	meraki "github.com/meraki/dashboard-api-go/v4/sdk"
 	v1 "github.com/secberus/go-push-api/types/v1"
 )
 
-var ConfigurationChanges = &Resource{
+var ConfigurationChanges = &Of[merakiOrganization, meraki.ResponseItemOrganizationsGetOrganizationConfigurationChanges]{
 	Table: &v1.Table{
 		Name:     "meraki_configuration_changes",
-		SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_TRUNCATE,
-		Columns:  columnsFor[meraki.ResponseItemOrganizationsGetOrganizationConfigurationChanges]("ts"),
+		SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
 	},
-	Resolver: getConfigurationChanges,
+	PrimaryKey: []string{"organization_id", "ts", "admin_id", "network_id", "page", "label"},
+	Resolver:   getConfigurationChanges,
 }
 
-func getConfigurationChanges(ctx context.Context, client *meraki.Client, org any) iter.Seq2[any, error] {
-	orgId := org.(meraki.ResponseItemOrganizationsGetOrganizations).ID
-	return func(yield func(any, error) bool) {
-		params := &meraki.GetOrganizationConfigurationChangesQueryParams{
-			Timespan: 24 * time.Hour.Seconds(),
+const (
+	// configurationChangesLookback is the maximum lookback period of the API,
+	// used when there is no checkpoint yet
+	configurationChangesLookback = 365 * 24 * time.Hour
+	configurationChangesPerPage  = 5000
+)
+
+func getConfigurationChanges(ctx context.Context, client *meraki.Client, org merakiOrganization) iter.Seq2[meraki.ResponseItemOrganizationsGetOrganizationConfigurationChanges, error] {
+	state := StateFrom(ctx)
+	return func(yield func(meraki.ResponseItemOrganizationsGetOrganizationConfigurationChanges, error) bool) {
+		// t0 is inclusive, so the changes at the high-water mark are fetched
+		// again and deduplicated by the primary key
+		t0 := time.Now().Add(-configurationChangesLookback + time.Minute).UTC().Format(time.RFC3339)
+		hwm := state.Cursor()
+		if hwm != "" {
+			t0 = hwm
 		}
-		rsl, rsp, err := client.Organizations.GetOrganizationConfigurationChanges(orgId, params)
-		if err != nil {
-			if rsp != nil && rsp.IsError() {
-				log.Printf("rsp status: %s, error: %+v\n", rsp.Status(), rsp.Error())
-				if err2, ok := rsp.Error().(error); ok {
-					err = errors.Join(err, err2)
-				}
+
+		pages := paginate("GetOrganizationConfigurationChanges", func(startingAfter string) (*meraki.ResponseOrganizationsGetOrganizationConfigurationChanges, *resty.Response, error) {
+			return client.Organizations.GetOrganizationConfigurationChanges(org.ID, &meraki.GetOrganizationConfigurationChangesQueryParams{
+				T0:            t0,
+				PerPage:       configurationChangesPerPage,
+				StartingAfter: startingAfter,
+			})
+		})
+		for i, err := range pages {
+			if err != nil {
+				yield(i, err)
+				return
+			}
+			if later(i.Ts, hwm) {
+				hwm = i.Ts
+				state.SetCursor(hwm)
 			}
-			yield(nil, fmt.Errorf("failed to GetOrganizationConfigurationChanges: %w", err))
-			return
-		}
-		if rsl == nil {
-			yield(nil, errors.New("received nil response from GetOrganizationConfigurationChanges"))
-			return
-		}
-		for _, i := range *rsl {
 			if !yield(i, nil) {
 				return
 			}
 		}
 	}
 }
+
+// later reports whether the ISO 8601 timestamp a is after b, treating an
+// unparseable b as the zero time.
+func later(a, b string) bool {
+	ta, err := time.Parse(time.RFC3339, a)
+	if err != nil {
+		return false
+	}
+	tb, _ := time.Parse(time.RFC3339, b)
+	return ta.After(tb)
+}
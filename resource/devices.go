@@ -19,49 +19,33 @@ package resource
 
 import (
 	"context"
-	"errors"
-	"fmt"
 	"iter"
-	"log"
 
+	"github.com/go-resty/resty/v2"
 	meraki "github.com/meraki/dashboard-api-go/v4/sdk"
 	v1 "github.com/secberus/go-push-api/types/v1"
 )
 
-var Devices = &Resource{
+var Devices = &Of[merakiNetwork, meraki.ResponseItemNetworksGetNetworkDevices]{
 	Table: &v1.Table{
 		Name:     "meraki_devices",
 		SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
-		Columns:  columnsFor[meraki.ResponseItemNetworksGetNetworkDevices]("serial"),
 	},
-	Resolver: getNetworkDevices,
-	Children: []*Resource{
+	PrimaryKey: []string{"serial"},
+	Resolver:   getNetworkDevices,
+	Key: Key[merakiDevice]{
+		Column: "device_serial",
+		Value:  func(d merakiDevice) string { return d.Serial },
+	},
+	Children: []Child[merakiDevice]{
 		Clients,
+		SwitchPorts,
+		SwitchPortStatuses,
 	},
 }
 
-func getNetworkDevices(ctx context.Context, client *meraki.Client, network any) iter.Seq2[any, error] {
-	networkId := network.(meraki.ResponseItemOrganizationsGetOrganizationNetworks).ID
-	return func(yield func(any, error) bool) {
-		rsl, rsp, err := client.Networks.GetNetworkDevices(networkId)
-		if err != nil {
-			if rsp != nil && rsp.IsError() {
-				log.Printf("rsp status: %s, error: %+v\n", rsp.Status(), rsp.Error())
-				if err2, ok := rsp.Error().(error); ok {
-					err = errors.Join(err, err2)
-				}
-			}
-			yield(nil, fmt.Errorf("failed to GetNetworkDevices: %w", err))
-			return
-		}
-		if rsl == nil {
-			yield(nil, errors.New("received nil response from GetNetworkDevices"))
-			return
-		}
-		for _, i := range *rsl {
-			if !yield(i, nil) {
-				return
-			}
-		}
-	}
+func getNetworkDevices(ctx context.Context, client *meraki.Client, network merakiNetwork) iter.Seq2[merakiDevice, error] {
+	return list("GetNetworkDevices", func() (*meraki.ResponseNetworksGetNetworkDevices, *resty.Response, error) {
+		return client.Networks.GetNetworkDevices(network.ID)
+	})
 }
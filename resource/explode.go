@@ -0,0 +1,66 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package resource
+
+import (
+	"context"
+	"iter"
+
+	meraki "github.com/meraki/dashboard-api-go/v4/sdk"
+	v1 "github.com/secberus/go-push-api/types/v1"
+)
+
+// element is an item of a slice field of a parent, numbered by its position.
+// Struct values are expanded into columns; other values are stored in a
+// single value column.
+type element[E any] struct {
+	Ordinal int `s6s:"ordinal,pk"`
+	Value   E   `s6s:",inline"`
+}
+
+// explode returns a child resource whose rows are the elements of a slice
+// field of its parents, so that they can be queried relationally. pk names
+// the parent key columns that identify a row together with its ordinal. The
+// rows of a parent are replaced on every run, as ordinals shift when
+// elements are removed.
+func explode[P, E any](table string, elements func(P) []E, pk ...string) *Of[P, element[E]] {
+	return &Of[P, element[E]]{
+		Table: &v1.Table{
+			Name:     table,
+			SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
+		},
+		PrimaryKey: pk,
+		Replace:    true,
+		Resolver: func(_ context.Context, _ *meraki.Client, parent P) iter.Seq2[element[E], error] {
+			return func(yield func(element[E], error) bool) {
+				for i, e := range elements(parent) {
+					if !yield(element[E]{Ordinal: i, Value: e}, nil) {
+						return
+					}
+				}
+			}
+		},
+	}
+}
+
+func deref[T any](p *[]T) []T {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package resource
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	v1 "github.com/secberus/go-push-api/types/v1"
+)
+
+// columnIndex maps every column of a table, by position, to the source of its
+// values: the field at a path of the items, or a parent key.
+type columnIndex struct {
+	item   reflect.Type
+	fields []*field
+}
+
+// indexFor builds the index of t for items of type item, whose parent key
+// columns are inherited. Columns that match neither a field nor a parent key,
+// duplicate columns and columns whose type differs from that of their field
+// are errors.
+func indexFor(t *v1.Table, item reflect.Type, inherited []string) (*columnIndex, error) {
+	fields, err := fieldsOf(t.Name, item)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*field, len(fields))
+	for i := range fields {
+		f := &fields[i]
+		if _, ok := byName[f.column.Name]; ok {
+			return nil, fmt.Errorf("more than one field of %s maps to column %q", item, f.column.Name)
+		}
+		byName[f.column.Name] = f
+	}
+
+	idx := &columnIndex{item: item, fields: make([]*field, len(t.Columns))}
+	seen := make(map[string]bool, len(t.Columns))
+	for i, c := range t.Columns {
+		if c == nil {
+			return nil, fmt.Errorf("column %d is nil", i)
+		}
+		if seen[c.Name] {
+			return nil, fmt.Errorf("duplicate column %q", c.Name)
+		}
+		seen[c.Name] = true
+
+		f, ok := byName[c.Name]
+		switch {
+		case ok:
+			if want, got := TypeName(f.column.DataType), TypeName(c.DataType); want != got {
+				return nil, fmt.Errorf("column %q is %s, but its field encodes as %s", c.Name, got, want)
+			}
+			idx.fields[i] = f
+		case slices.Contains(inherited, c.Name):
+			if c.DataType.GetText() == nil {
+				return nil, fmt.Errorf("parent key column %q is %s, not text", c.Name, TypeName(c.DataType))
+			}
+		default:
+			return nil, fmt.Errorf("column %q matches neither a field of %s nor a parent key", c.Name, item)
+		}
+	}
+	return idx, nil
+}
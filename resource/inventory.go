@@ -0,0 +1,47 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package resource
+
+import (
+	"context"
+	"iter"
+
+	"github.com/go-resty/resty/v2"
+	meraki "github.com/meraki/dashboard-api-go/v4/sdk"
+	v1 "github.com/secberus/go-push-api/types/v1"
+)
+
+// InventoryDevices covers every device claimed by an organization, including
+// those not assigned to any network, which Devices cannot see.
+var InventoryDevices = &Of[merakiOrganization, meraki.ResponseItemOrganizationsGetOrganizationInventoryDevices]{
+	Table: &v1.Table{
+		Name:     "meraki_inventory_devices",
+		SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
+	},
+	PrimaryKey: []string{"organization_id", "serial"},
+	Resolver:   getOrganizationInventoryDevices,
+}
+
+func getOrganizationInventoryDevices(_ context.Context, client *meraki.Client, org merakiOrganization) iter.Seq2[meraki.ResponseItemOrganizationsGetOrganizationInventoryDevices, error] {
+	return paginate("GetOrganizationInventoryDevices", func(startingAfter string) (*meraki.ResponseOrganizationsGetOrganizationInventoryDevices, *resty.Response, error) {
+		return client.Organizations.GetOrganizationInventoryDevices(org.ID, &meraki.GetOrganizationInventoryDevicesQueryParams{
+			PerPage:       meraki.PAGINATION_PER_PAGE,
+			StartingAfter: startingAfter,
+		})
+	})
+}
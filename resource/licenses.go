@@ -0,0 +1,74 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package resource
+
+import (
+	"context"
+	"iter"
+	"slices"
+
+	"github.com/go-resty/resty/v2"
+	meraki "github.com/meraki/dashboard-api-go/v4/sdk"
+	v1 "github.com/secberus/go-push-api/types/v1"
+)
+
+var LicensesOverview = &Of[merakiOrganization, meraki.ResponseOrganizationsGetOrganizationLicensesOverview]{
+	Table: &v1.Table{
+		Name:     "meraki_licenses_overview",
+		SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
+	},
+	PrimaryKey: []string{"organization_id"},
+	Resolver:   getOrganizationLicensesOverview,
+}
+
+func getOrganizationLicensesOverview(_ context.Context, client *meraki.Client, org merakiOrganization) iter.Seq2[meraki.ResponseOrganizationsGetOrganizationLicensesOverview, error] {
+	if !hasLicensingModel(org, "co-term", "per-device") {
+		return func(func(meraki.ResponseOrganizationsGetOrganizationLicensesOverview, error) bool) {}
+	}
+	return single("GetOrganizationLicensesOverview", func() (*meraki.ResponseOrganizationsGetOrganizationLicensesOverview, *resty.Response, error) {
+		return client.Organizations.GetOrganizationLicensesOverview(org.ID)
+	})
+}
+
+var Licenses = &Of[merakiOrganization, meraki.ResponseItemOrganizationsGetOrganizationLicenses]{
+	Table: &v1.Table{
+		Name:     "meraki_licenses",
+		SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
+	},
+	PrimaryKey: []string{"organization_id", "id"},
+	Resolver:   getOrganizationLicenses,
+}
+
+func getOrganizationLicenses(_ context.Context, client *meraki.Client, org merakiOrganization) iter.Seq2[meraki.ResponseItemOrganizationsGetOrganizationLicenses, error] {
+	if !hasLicensingModel(org, "per-device") {
+		return func(func(meraki.ResponseItemOrganizationsGetOrganizationLicenses, error) bool) {}
+	}
+	return paginate("GetOrganizationLicenses", func(startingAfter string) (*meraki.ResponseOrganizationsGetOrganizationLicenses, *resty.Response, error) {
+		return client.Organizations.GetOrganizationLicenses(org.ID, &meraki.GetOrganizationLicensesQueryParams{
+			PerPage:       meraki.PAGINATION_PER_PAGE,
+			StartingAfter: startingAfter,
+		})
+	})
+}
+
+// hasLicensingModel reports whether org uses one of the licensing models,
+// i.e. "co-term", "per-device" or "subscription". The license endpoints fail
+// with 400 for organizations of other models.
+func hasLicensingModel(org merakiOrganization, models ...string) bool {
+	return org.Licensing != nil && slices.Contains(models, org.Licensing.Model)
+}
@@ -19,12 +19,16 @@ package resource
 
 import (
 	"bytes"
+	"encoding"
 	"encoding/json"
 	"fmt"
-	"log"
+	"maps"
+	"math"
 	"net"
 	"net/netip"
 	"reflect"
+	"strings"
+	"sync"
 	"time"
 
 	"google.golang.org/protobuf/proto"
@@ -32,29 +36,48 @@ import (
 	v1 "github.com/secberus/go-push-api/types/v1"
 )
 
-func columnsFor[T any](pk string) []*v1.Column {
-	t := reflect.TypeFor[T]()
-
-	if t.Kind() == reflect.Ptr {
+// columnsFor returns a column per field of the items of table, of type t,
+// as named and typed by its s6s struct tag or column override. pk names the
+// primary key columns, by field or column name; names that match no field
+// are parent key columns, which are appended and checked against the
+// resource tree by Validate. Invalid tags and overrides are reported by the
+// returned error.
+func columnsFor(table string, t reflect.Type, pk []string) ([]*v1.Column, error) {
+	if t.Kind() == reflect.Pointer {
 		t = t.Elem()
 	}
-	if t.Kind() != reflect.Struct {
-		return nil
-	}
 
-	n := t.NumField()
-	columns := make([]*v1.Column, n)
+	fields, err := fieldsOf(table, t)
+	columns := make([]*v1.Column, 0, len(fields)+len(pk))
+	matched := make([]bool, len(pk))
 
-	for i := 0; i < n; i++ {
-		f := t.Field(i)
+	for _, fc := range fields {
+		c := fc.column
+		f := t.FieldByIndex(fc.path)
+		for j, k := range pk {
+			if fc.raw == nil && (k == f.Name || k == c.Name) {
+				c.PrimaryKey = true
+				matched[j] = true
+			}
+		}
+		if c.PrimaryKey {
+			// keys are never null, not even those of coerced columns
+			c.Nillable = false
+		}
+		columns = append(columns, c)
+	}
 
-		columns[i] = columnFor(f)
-		if f.Name == pk || columns[i].Name == pk {
-			columns[i].PrimaryKey = true
+	for j, k := range pk {
+		if !matched[j] {
+			columns = append(columns, &v1.Column{
+				Name:       k,
+				PrimaryKey: true,
+				DataType:   &v1.DataType{Union: _Text},
+			})
 		}
 	}
 
-	return columns
+	return columns, err
 }
 
 var (
@@ -73,86 +96,150 @@ var (
 	_Macaddr     = &v1.DataType_Macaddr{Macaddr: &v1.Macaddr{}}
 )
 
-func columnFor(f reflect.StructField) *v1.Column {
+// TypeName returns the name of the type of dt, e.g. "inet".
+func TypeName(dt *v1.DataType) string {
+	if dt == nil {
+		return ""
+	}
+	m := dt.ProtoReflect()
+	if fd := m.WhichOneof(m.Descriptor().Oneofs().ByName("union")); fd != nil {
+		return string(fd.Name())
+	}
+	return ""
+}
+
+// typeColumns are the column types of struct fields by Go type, taking
+// precedence over kindColumns.
+var typeColumns = map[reflect.Type]*v1.DataType{
+	reflect.TypeFor[bytes.Buffer]():     dataTypes["bytea"],
+	reflect.TypeFor[time.Time]():        dataTypes["timestamptz"],
+	reflect.TypeFor[net.IP]():           dataTypes["inet"],
+	reflect.TypeFor[net.IPAddr]():       dataTypes["inet"],
+	reflect.TypeFor[netip.Addr]():       dataTypes["inet"],
+	reflect.TypeFor[net.IPNet]():        dataTypes["cidr"],
+	reflect.TypeFor[netip.Prefix]():     dataTypes["cidr"],
+	reflect.TypeFor[net.HardwareAddr](): dataTypes["macaddr"],
+}
+
+// kindColumns are the column types of struct fields by kind. Integers are
+// mapped to the smallest type that holds every value of the kind, except for
+// int, which Meraki uses for small numbers throughout and which is range
+// checked when encoding instead. Slices and arrays of bytes are bytea.
+var kindColumns = map[reflect.Kind]*v1.DataType{
+	reflect.String:     dataTypes["text"],
+	reflect.Bool:       dataTypes["boolean"],
+	reflect.Int:        dataTypes["integer"],
+	reflect.Int8:       dataTypes["smallint"],
+	reflect.Int16:      dataTypes["smallint"],
+	reflect.Int32:      dataTypes["integer"],
+	reflect.Int64:      dataTypes["bigint"],
+	reflect.Uint:       dataTypes["bigint"],
+	reflect.Uint8:      dataTypes["smallint"],
+	reflect.Uint16:     dataTypes["integer"],
+	reflect.Uint32:     dataTypes["bigint"],
+	reflect.Uint64:     dataTypes["bigint"],
+	reflect.Uintptr:    dataTypes["bigint"],
+	reflect.Float32:    dataTypes["real"],
+	reflect.Float64:    dataTypes["double"],
+	reflect.Complex64:  dataTypes["text"],
+	reflect.Complex128: dataTypes["text"],
+	reflect.Array:      dataTypes["jsonb"],
+	reflect.Slice:      dataTypes["jsonb"],
+	reflect.Map:        dataTypes["jsonb"],
+	reflect.Struct:     dataTypes["jsonb"],
+	reflect.Interface:  dataTypes["jsonb"],
+}
+
+// columnFor returns the column of field f, or an error for kinds that cannot
+// be stored, such as channels and functions.
+func columnFor(f reflect.StructField, tag columnTag) (*v1.Column, error) {
 	c := v1.Column{
-		Name:     snakecase(f.Name),
-		DataType: &v1.DataType{},
+		Name:       columnName(f, tag),
+		PrimaryKey: tag.PK,
 	}
 
 	t := f.Type
-	if t.Kind() == reflect.Pointer {
-		c.Nillable = true
+	for t.Kind() == reflect.Pointer {
 		t = t.Elem()
 	}
+	switch f.Type.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		c.Nillable = true
+	}
 
-	u := &c.DataType.Union
-
-	switch t.Kind() {
-	case reflect.String:
-		*u = _Text
-	case reflect.Bool:
-		*u = _Boolean
-	case reflect.Int, reflect.Uint16, reflect.Int32:
-		*u = _Integer
-	case reflect.Int8, reflect.Uint8, reflect.Int16:
-		*u = _Smallint
-	case reflect.Int64, reflect.Uint32:
-		*u = _Bigint
-	case reflect.Float32:
-		*u = _Real
-	case reflect.Float64:
-		*u = _Double
-	case reflect.Array, reflect.Slice:
-		if t == reflect.TypeFor[net.IP]() {
-			*u = _Inet
-		} else if t == reflect.TypeFor[net.HardwareAddr]() {
-			*u = _Macaddr
-		} else {
-			switch t.Elem().Kind() {
-			case reflect.Int8, reflect.Uint8:
-				*u = _Bytea
-			default:
-				*u = _Jsonb
-			}
-		}
-	case reflect.Map:
-		if t.Key().Kind() == reflect.String {
-			*u = _Jsonb
-		} else {
-			// TODO better handle non-string-keyed maps
-			log.Printf("unhandled type for struct field %q: map with %s keys\n", f.Name, t.Key())
-		}
-	case reflect.Struct:
-		switch t {
-		case reflect.TypeFor[bytes.Buffer]():
-			*u = _Bytea
-		case reflect.TypeFor[time.Time]():
-			*u = _Timestamptz
-		case reflect.TypeFor[net.IPAddr](), reflect.TypeFor[netip.Addr]():
-			*u = _Inet
-		case reflect.TypeFor[net.IPNet](), reflect.TypeFor[netip.Prefix]():
-			*u = _Cidr
-		default:
-			*u = _Jsonb
+	if tag.Type == nil && t.Kind() == reflect.String {
+		if co := coercionFor(c.Name); co != nil {
+			tag.Type = co.dataType
 		}
-	default:
-		log.Printf("unhandled type for struct field %q: %s\n", f.Name, f.Type)
-		*u = _Jsonb
 	}
-	return &c
+
+	dt := tag.Type
+	if dt == nil {
+		dt = typeColumns[t]
+	}
+	if dt == nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && isByte(t.Elem()) {
+		dt = dataTypes["bytea"]
+	}
+	if dt == nil {
+		dt = kindColumns[t.Kind()]
+	}
+	if dt == nil {
+		return nil, fmt.Errorf("unsupported type %s of field %q", f.Type, f.Name)
+	}
+	c.DataType = proto.Clone(dt).(*v1.DataType)
+
+	// empty strings are encoded as null in columns of other types
+	if t.Kind() == reflect.String && c.DataType.GetText() == nil {
+		c.Nillable = true
+	}
+	return &c, nil
+}
+
+func isByte(t reflect.Type) bool {
+	return t.Kind() == reflect.Uint8 || t.Kind() == reflect.Int8
 }
 
-func RecordFor(t *v1.Table, v any) (*v1.Record, error) {
-	cs, err := columnValuesFor(t, v)
+// Record encodes v, an item of rc, as a record of its table. The values of
+// parent key columns are taken from keys.
+func (rc *Resource) Record(v any, keys map[string]string) (*v1.Record, error) {
+	idx, err := rc.columnIndex()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Record for table %q: %w", t.Name, err)
+		return nil, fmt.Errorf("invalid columns for table %q: %w", rc.Table.Name, err)
+	}
+	cs, err := columnValuesFor(rc.Table, idx, v, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Record for table %q: %w", rc.Table.Name, err)
 	}
 	return &v1.Record{
-		TableName: t.Name,
+		TableName: rc.Table.Name,
 		Columns:   cs,
 	}, nil
 }
 
+// ParentKey returns a record of the primary key columns of rc that are
+// parent keys, with values taken from keys, which identifies the rows of a
+// single parent.
+func (rc *Resource) ParentKey(keys map[string]string) (*v1.Record, error) {
+	idx, err := rc.columnIndex()
+	if err != nil {
+		return nil, fmt.Errorf("invalid columns for table %q: %w", rc.Table.Name, err)
+	}
+	rec := &v1.Record{TableName: rc.Table.Name}
+	for i, c := range rc.Table.Columns {
+		if !c.PrimaryKey || idx.fields[i] != nil {
+			continue
+		}
+		k, ok := keys[c.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing value for parent key column %q of table %q", c.Name, rc.Table.Name)
+		}
+		kc := copyColumn(c)
+		kc.DataType.GetText().Value = ptr(k)
+		rec.Columns = append(rec.Columns, kc)
+	}
+	return rec, nil
+}
+
 func copyColumn(c *v1.Column) *v1.Column {
 	return &v1.Column{
 		Name:     c.Name,
@@ -162,90 +249,59 @@ func copyColumn(c *v1.Column) *v1.Column {
 
 const pgTstzFmt = "2006-01-02 15:04:05.999999999Z07:00"
 
-func columnValuesFor(t *v1.Table, v any) ([]*v1.Column, error) {
+func columnValuesFor(t *v1.Table, idx *columnIndex, v any, keys map[string]string) ([]*v1.Column, error) {
 	rv := reflect.ValueOf(v)
+	if rv.Type() != idx.item {
+		return nil, fmt.Errorf("expected an item of type %s, got %s", idx.item, rv.Type())
+	}
 
 	row := make([]*v1.Column, len(t.Columns))
 	for i, c := range t.Columns {
-		if c == nil {
-			continue
-		}
 		row[i] = copyColumn(c)
 
-		rcv := rv.Field(i)
-		cv := rcv.Interface()
-		dt := row[i].DataType
-
-		var err error
-		if tt := dt.GetText(); tt != nil {
-			tt.Value, err = box[string](cv)
-		} else if bt := dt.GetBoolean(); bt != nil {
-			bt.Value, err = box[bool](cv)
-		} else if it := dt.GetInteger(); it != nil {
-			it.Value, err = tryAll(cv, box[int32], boxWith(ii32), boxWith(u16i32))
-		} else if st := dt.GetSmallint(); st != nil {
-			st.Value, err = tryAll(cv, boxWith(i8i32), boxWith(u8i32), boxWith(i16i32))
-		} else if bgt := dt.GetBigint(); bgt != nil {
-			bgt.Value, err = tryAll(cv, box[int64], boxWith(u32i64))
-		} else if ft := dt.GetReal(); ft != nil {
-			ft.Value, err = box[float32](cv)
-		} else if dbt := dt.GetDouble(); dbt != nil {
-			dbt.Value, err = box[float64](cv)
-		} else if bat := dt.GetBytea(); bat != nil {
-			switch u := cv.(type) {
-			case []uint8:
-				bat.Value = u
-			case []int8:
-				bat.Value = converts(u, i8u8)
-			case bytes.Buffer:
-				bat.Value = u.Bytes()
-			case *bytes.Buffer:
-				bat.Value = u.Bytes()
-			default:
-				if rcv.Kind() == reflect.Array {
-					switch rcv.Elem().Kind() {
-					case reflect.Uint8:
-						bat.Value = rcv.Bytes()
-					case reflect.Int8:
-						bat.Value = converts(rcv.Slice(0, rcv.Len()).Interface().([]int8), i8u8)
-					}
-				}
-			}
-		} else if tmt := dt.GetTimestamptz(); tmt != nil {
-			switch u := cv.(type) {
-			case time.Time:
-				tmt.Value = ptr(u.Truncate(time.Microsecond).Format(pgTstzFmt))
-			case *time.Time:
-				tmt.Value = ptr(u.Truncate(time.Microsecond).Format(pgTstzFmt))
-			}
-		} else if intt := dt.GetInet(); intt != nil {
-			switch u := cv.(type) {
-			case net.IP:
-				intt.Value = ptr(u.String())
-			case net.IPAddr:
-				intt.Value = ptr(u.IP.String())
-			case netip.Addr:
-				intt.Value = ptr(u.String())
+		f := idx.fields[i]
+		if f == nil {
+			k, ok := keys[c.Name]
+			if !ok {
+				return nil, fmt.Errorf("missing value for parent key column %q", c.Name)
 			}
-		} else if mt := dt.GetMacaddr(); mt != nil {
-			switch u := cv.(type) {
-			case net.HardwareAddr:
-				mt.Value = ptr(u.String())
-			}
-		} else if cdt := dt.GetCidr(); cdt != nil {
-			switch u := cv.(type) {
-			case net.IPNet:
-				cdt.Value = ptr(u.String())
-			case netip.Prefix:
-				cdt.Value = ptr(u.String())
+			row[i].DataType.GetText().Value = ptr(k)
+			continue
+		}
+
+		fv, err := rv.FieldByIndexErr(f.path)
+		if err == nil {
+			fv = indirect(fv)
+		}
+		if err != nil || !fv.IsValid() {
+			// a nil pointer, possibly on the path of a flattened field
+			if !c.Nillable {
+				return nil, fmt.Errorf("nil value for column %q, which is not nillable", c.Name)
 			}
-		} else if jt := dt.GetJsonb(); jt != nil {
-			var enc []byte
-			if enc, err = json.Marshal(cv); err == nil {
-				jt.Value = ptr(string(enc))
+			continue
+		}
+		if c.PrimaryKey && fv.Kind() == reflect.String && fv.String() == "" && c.DataType.GetText() == nil {
+			return nil, fmt.Errorf("empty value for primary key column %q", c.Name)
+		}
+
+		if f.raw != nil {
+			// the original of a value that fails to parse as the coerced
+			// type, which is sent as null in its own column
+			if encodeValue(proto.Clone(f.raw).(*v1.DataType), fv) != nil {
+				row[i].DataType.GetText().Value = ptr(fv.String())
 			}
+			continue
 		}
 
+		err = encodeValue(row[i].DataType, fv)
+		if err != nil && f.coerced && c.Nillable {
+			// values of coerced columns that fail to parse are sent as null,
+			// as the column only accepts values of its type, and kept as text
+			// in the raw column
+			countCoercionFailure(t.Name)
+			row[i] = copyColumn(c)
+			err = nil
+		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to encode column %q: %w", c.Name, err)
 		}
@@ -253,3 +309,281 @@ func columnValuesFor(t *v1.Table, v any) ([]*v1.Column, error) {
 
 	return row, nil
 }
+
+// coercion gives string fields whose column name matches another type, as
+// the Meraki SDK models addresses and timestamps as strings. Explicit column
+// types take precedence. Values that fail to parse are sent as null, and kept
+// as text in an additional <column>_raw column.
+type coercion struct {
+	match    func(name string) bool
+	dataType *v1.DataType
+}
+
+var coercions = []coercion{
+	{
+		match: func(name string) bool {
+			return name == "ip" || strings.HasSuffix(name, "_ip")
+		},
+		dataType: &v1.DataType{Union: _Inet},
+	},
+	{
+		match: func(name string) bool {
+			return name == "mac" || strings.HasSuffix(name, "_mac")
+		},
+		dataType: &v1.DataType{Union: _Macaddr},
+	},
+	{
+		match: func(name string) bool {
+			return name == "ts" || name == "first_seen" || name == "last_seen" || strings.HasSuffix(name, "_at")
+		},
+		dataType: &v1.DataType{Union: _Timestamptz},
+	},
+}
+
+func coercionFor(name string) *coercion {
+	for i := range coercions {
+		if coercions[i].match(name) {
+			return &coercions[i]
+		}
+	}
+	return nil
+}
+
+var coercionFailures = struct {
+	sync.Mutex
+	tables map[string]int64
+}{tables: make(map[string]int64)}
+
+func countCoercionFailure(table string) {
+	coercionFailures.Lock()
+	defer coercionFailures.Unlock()
+	coercionFailures.tables[table]++
+}
+
+// CoercionFailures returns the number of values per table that failed to
+// parse as the type of a coerced column since the process started.
+func CoercionFailures() map[string]int64 {
+	coercionFailures.Lock()
+	defer coercionFailures.Unlock()
+	return maps.Clone(coercionFailures.tables)
+}
+
+// indirect follows pointers and interfaces, returning the zero Value for nil
+// values, including nil slices and maps.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.IsNil() {
+		return reflect.Value{}
+	}
+	return v
+}
+
+// encodeValue sets the value of dt to v. Strings are parsed into columns of
+// other types, with empty strings encoded as null.
+func encodeValue(dt *v1.DataType, v reflect.Value) error {
+	if v.Kind() == reflect.String && v.String() == "" && dt.GetText() == nil {
+		return nil
+	}
+
+	switch u := dt.Union.(type) {
+	case *v1.DataType_Text:
+		if v.Kind() == reflect.String {
+			u.Text.Value = ptr(v.String())
+		} else {
+			u.Text.Value = ptr(fmt.Sprint(v.Interface()))
+		}
+	case *v1.DataType_Boolean:
+		if v.Kind() != reflect.Bool {
+			return mismatch(v, "boolean")
+		}
+		u.Boolean.Value = ptr(v.Bool())
+	case *v1.DataType_Smallint:
+		i, err := intValue(v, 16)
+		if err != nil {
+			return err
+		}
+		// there is no 16-bit protobuf type
+		u.Smallint.Value = ptr(int32(i))
+	case *v1.DataType_Integer:
+		i, err := intValue(v, 32)
+		if err != nil {
+			return err
+		}
+		u.Integer.Value = ptr(int32(i))
+	case *v1.DataType_Bigint:
+		i, err := intValue(v, 64)
+		if err != nil {
+			return err
+		}
+		u.Bigint.Value = ptr(i)
+	case *v1.DataType_Real:
+		if !v.CanFloat() {
+			return mismatch(v, "real")
+		}
+		u.Real.Value = ptr(float32(v.Float()))
+	case *v1.DataType_Double:
+		if !v.CanFloat() {
+			return mismatch(v, "double")
+		}
+		u.Double.Value = ptr(v.Float())
+	case *v1.DataType_Bytea:
+		switch {
+		case v.Type() == reflect.TypeFor[bytes.Buffer]():
+			b := v.Interface().(bytes.Buffer)
+			u.Bytea.Value = bytes.Clone(b.Bytes())
+		case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && isByte(v.Type().Elem()):
+			b := make([]byte, v.Len())
+			for i := range b {
+				if e := v.Index(i); e.CanUint() {
+					b[i] = byte(e.Uint())
+				} else {
+					b[i] = byte(e.Int())
+				}
+			}
+			u.Bytea.Value = b
+		default:
+			return mismatch(v, "bytea")
+		}
+	case *v1.DataType_Timestamptz:
+		switch t := v.Interface().(type) {
+		case time.Time:
+			u.Timestamptz.Value = ptr(t.Truncate(time.Microsecond).Format(pgTstzFmt))
+		default:
+			return parseInto(&u.Timestamptz.Value, v, "timestamptz", parseTimestamptz)
+		}
+	case *v1.DataType_Inet:
+		switch a := v.Interface().(type) {
+		case net.IP:
+			u.Inet.Value = ptr(a.String())
+		case net.IPAddr:
+			u.Inet.Value = ptr(a.IP.String())
+		case netip.Addr:
+			u.Inet.Value = ptr(a.WithZone("").String())
+		default:
+			return parseInto(&u.Inet.Value, v, "inet", parseInet)
+		}
+	case *v1.DataType_Cidr:
+		switch p := v.Interface().(type) {
+		case net.IPNet:
+			u.Cidr.Value = ptr(p.String())
+		case netip.Prefix:
+			u.Cidr.Value = ptr(p.Masked().String())
+		default:
+			return parseInto(&u.Cidr.Value, v, "cidr", parseCidr)
+		}
+	case *v1.DataType_Macaddr:
+		switch m := v.Interface().(type) {
+		case net.HardwareAddr:
+			u.Macaddr.Value = ptr(m.String())
+		default:
+			return parseInto(&u.Macaddr.Value, v, "macaddr", parseMacaddr)
+		}
+	case *v1.DataType_Jsonb:
+		enc, err := json.Marshal(jsonValue(v))
+		if err != nil {
+			return err
+		}
+		u.Jsonb.Value = ptr(string(enc))
+	default:
+		return fmt.Errorf("unsupported column type %s", TypeName(dt))
+	}
+	return nil
+}
+
+func mismatch(v reflect.Value, typ string) error {
+	return fmt.Errorf("cannot encode %s as %s", v.Type(), typ)
+}
+
+// intValue returns the integer held by v if it fits into bits.
+func intValue(v reflect.Value, bits int) (int64, error) {
+	var i int64
+	switch {
+	case v.CanInt():
+		i = v.Int()
+	case v.CanUint():
+		if v.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("%d overflows a %d-bit integer", v.Uint(), bits)
+		}
+		i = int64(v.Uint())
+	default:
+		return 0, mismatch(v, "integer")
+	}
+	if min, max := int64(-1)<<(bits-1), int64(1)<<(bits-1)-1; i < min || i > max {
+		return 0, fmt.Errorf("%d overflows a %d-bit integer", i, bits)
+	}
+	return i, nil
+}
+
+func parseInto(dst **string, v reflect.Value, typ string, parse func(string) (string, error)) error {
+	if v.Kind() != reflect.String {
+		return mismatch(v, typ)
+	}
+	s, err := parse(v.String())
+	if err != nil {
+		return err
+	}
+	*dst = &s
+	return nil
+}
+
+// jsonValue returns v for encoding/json, converting maps whose keys it cannot
+// encode into maps with string keys.
+func jsonValue(v reflect.Value) any {
+	if v.Kind() == reflect.Map && !jsonKey(v.Type().Key()) {
+		m := make(map[string]any, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			m[fmt.Sprint(it.Key().Interface())] = it.Value().Interface()
+		}
+		return m
+	}
+	return v.Interface()
+}
+
+func jsonKey(t reflect.Type) bool {
+	if t.Kind() == reflect.String || t.Implements(reflect.TypeFor[encoding.TextMarshaler]()) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+func parseTimestamptz(s string) (string, error) {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return "", err
+	}
+	return t.Truncate(time.Microsecond).Format(pgTstzFmt), nil
+}
+
+func parseInet(s string) (string, error) {
+	a, err := netip.ParseAddr(s)
+	if err != nil {
+		return "", err
+	}
+	return a.String(), nil
+}
+
+func parseCidr(s string) (string, error) {
+	p, err := netip.ParsePrefix(s)
+	if err != nil {
+		return "", err
+	}
+	return p.Masked().String(), nil
+}
+
+func parseMacaddr(s string) (string, error) {
+	mac, err := net.ParseMAC(s)
+	if err != nil {
+		return "", err
+	}
+	return mac.String(), nil
+}
@@ -0,0 +1,426 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"net"
+	"net/netip"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	v1 "github.com/secberus/go-push-api/types/v1"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestRoundTrip(t *testing.T) {
+	_, ipNet, _ := net.ParseCIDR("10.0.0.0/8")
+	mac, _ := net.ParseMAC("00:11:22:aa:bb:cc")
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 123456000, time.UTC)
+
+	type nested struct {
+		A int
+		B []string
+	}
+
+	tests := []struct {
+		name  string
+		value any
+		// field is the type of the field holding value, if not its own type
+		field    reflect.Type
+		tag      string
+		typ      string
+		nillable bool
+		// want is the decoded value, if it differs from value
+		want any
+		// encoded is the expected encoding, for values that cannot be decoded
+		encoded string
+		// null is set for non-nil values that are encoded as null
+		null bool
+		err  bool
+	}{
+		{name: "string", value: "a", typ: "text"},
+		{name: "*string", value: ptr("a"), typ: "text", nillable: true},
+		{name: "nil *string", value: (*string)(nil), typ: "text", nillable: true},
+		{name: "bool", value: true, typ: "boolean"},
+		{name: "*bool", value: ptr(false), typ: "boolean", nillable: true},
+		{name: "int", value: 42, typ: "integer"},
+		{name: "int overflow", value: 1 << 40, typ: "integer", err: true},
+		{name: "*int", value: ptr(-7), typ: "integer", nillable: true},
+		{name: "nil *int", value: (*int)(nil), typ: "integer", nillable: true},
+		{name: "int8", value: int8(math.MinInt8), typ: "smallint"},
+		{name: "int16", value: int16(math.MaxInt16), typ: "smallint"},
+		{name: "int32", value: int32(math.MinInt32), typ: "integer"},
+		{name: "int64", value: int64(math.MaxInt64), typ: "bigint"},
+		{name: "*int64", value: ptr(int64(math.MinInt64)), typ: "bigint", nillable: true},
+		{name: "nil *int64", value: (*int64)(nil), typ: "bigint", nillable: true},
+		{name: "uint", value: uint(7), typ: "bigint"},
+		{name: "uint8", value: uint8(math.MaxUint8), typ: "smallint"},
+		{name: "uint16", value: uint16(math.MaxUint16), typ: "integer"},
+		{name: "uint32", value: uint32(math.MaxUint32), typ: "bigint"},
+		{name: "uint64", value: uint64(math.MaxInt64), typ: "bigint"},
+		{name: "uint64 overflow", value: uint64(math.MaxUint64), typ: "bigint", err: true},
+		{name: "*uint64", value: ptr(uint64(1)), typ: "bigint", nillable: true},
+		{name: "uintptr", value: uintptr(8), typ: "bigint"},
+		{name: "float32", value: float32(1.5), typ: "real"},
+		{name: "*float32", value: ptr(float32(-2.25)), typ: "real", nillable: true},
+		{name: "nil *float32", value: (*float32)(nil), typ: "real", nillable: true},
+		{name: "float64", value: math.Pi, typ: "double"},
+		{name: "nil *float64", value: (*float64)(nil), typ: "double", nillable: true},
+		{name: "complex128", value: complex(1, -2), typ: "text"},
+		{name: "[]byte", value: []byte{0, 1, 255}, typ: "bytea", nillable: true},
+		{name: "nil []byte", value: []byte(nil), typ: "bytea", nillable: true},
+		{name: "[4]byte", value: [4]byte{1, 2, 3, 4}, typ: "bytea"},
+		{name: "[]int8", value: []int8{-1, 0, 1}, typ: "bytea", nillable: true},
+		{name: "[2]int8", value: [2]int8{-128, 127}, typ: "bytea"},
+		{name: "bytes.Buffer", value: *bytes.NewBufferString("abc"), typ: "bytea"},
+		{name: "time.Time", value: ts, typ: "timestamptz"},
+		{name: "*time.Time", value: &ts, typ: "timestamptz", nillable: true},
+		{name: "net.IP", value: net.IPv4(10, 0, 0, 1), typ: "inet", nillable: true},
+		{name: "net.IPAddr", value: net.IPAddr{IP: net.ParseIP("fe80::1")}, typ: "inet"},
+		{name: "netip.Addr", value: netip.MustParseAddr("192.168.1.1"), typ: "inet"},
+		{name: "net.IPNet", value: *ipNet, typ: "cidr"},
+		{name: "netip.Prefix", value: netip.MustParsePrefix("2001:db8::/32"), typ: "cidr"},
+		{name: "net.HardwareAddr", value: mac, typ: "macaddr", nillable: true},
+		{name: "[]string", value: []string{"a", "b"}, typ: "jsonb", nillable: true},
+		{name: "nil []string", value: []string(nil), typ: "jsonb", nillable: true},
+		{name: "[]int", value: []int{1, 2}, typ: "jsonb", nillable: true},
+		{name: "map[string]int", value: map[string]int{"a": 1}, typ: "jsonb", nillable: true},
+		{name: "map[int]string", value: map[int]string{1: "a"}, typ: "jsonb", nillable: true},
+		{name: "map[float64]string", value: map[float64]string{1.5: "a"}, typ: "jsonb", nillable: true, encoded: `{"1.5":"a"}`},
+		{name: "nil map", value: map[string]int(nil), typ: "jsonb", nillable: true},
+		{name: "struct", value: nested{A: 1, B: []string{"x"}}, typ: "jsonb"},
+		{name: "*struct", value: &nested{A: 2}, typ: "jsonb", nillable: true},
+		{name: "nil *struct", value: (*nested)(nil), typ: "jsonb", nillable: true},
+		{name: "any", value: map[string]any{"a": "b"}, field: reflect.TypeFor[any](), typ: "jsonb", nillable: true},
+		{name: "nil any", value: nil, field: reflect.TypeFor[any](), typ: "jsonb", nillable: true},
+		{name: "string as inet", value: "10.0.0.1", tag: ",type=inet", typ: "inet", nillable: true},
+		{name: "empty string as inet", value: "", tag: ",type=inet", typ: "inet", nillable: true, null: true},
+		{name: "invalid string as inet", value: "x", tag: ",type=inet", typ: "inet", nillable: true, err: true},
+		{name: "string as cidr", value: "10.1.2.3/8", tag: ",type=cidr", typ: "cidr", nillable: true, want: "10.0.0.0/8"},
+		{name: "string as macaddr", value: "00-11-22-AA-BB-CC", tag: ",type=macaddr", typ: "macaddr", nillable: true, want: "00:11:22:aa:bb:cc"},
+		{name: "string as timestamptz", value: "2025-01-02T03:04:05Z", tag: ",type=timestamptz", typ: "timestamptz", nillable: true, want: "2025-01-02 03:04:05Z"},
+		{name: "int as text", value: 5, tag: ",type=text", typ: "text", want: 5},
+		{name: "bool as integer", value: true, tag: ",type=integer", typ: "integer", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// wrap the value into a struct, as columns are derived from fields
+			ft := tt.field
+			if ft == nil {
+				ft = reflect.TypeOf(tt.value)
+			}
+			st := reflect.StructOf([]reflect.StructField{{
+				Name: "V",
+				Type: ft,
+				Tag:  reflect.StructTag(`s6s:"` + tt.tag + `"`),
+			}})
+
+			fields, err := fieldsOf("", st)
+			if err != nil {
+				t.Fatalf("fieldsOf: %s", err)
+			}
+			c := fields[0].column
+			if got := TypeName(c.DataType); got != tt.typ {
+				t.Errorf("column type = %s, want %s", got, tt.typ)
+			}
+			if c.Nillable != tt.nillable {
+				t.Errorf("nillable = %t, want %t", c.Nillable, tt.nillable)
+			}
+
+			table := &v1.Table{Name: "t", Columns: []*v1.Column{c}}
+			idx, err := indexFor(table, st, nil)
+			if err != nil {
+				t.Fatalf("indexFor: %s", err)
+			}
+			sv := reflect.New(st).Elem()
+			if tt.value != nil {
+				sv.Field(0).Set(reflect.ValueOf(tt.value))
+			}
+			row, err := columnValuesFor(table, idx, sv.Interface(), nil)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", row[0])
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("columnValuesFor: %s", err)
+			}
+
+			v, ok := unionValue(row[0].DataType)
+			if tt.encoded != "" {
+				if !ok || v.String() != tt.encoded {
+					t.Errorf("encoded as %v, want %s", v, tt.encoded)
+				}
+				return
+			}
+
+			want := tt.value
+			if tt.want != nil {
+				want = tt.want
+			}
+			if null := tt.null || !indirect(reflect.ValueOf(tt.value)).IsValid(); null == ok {
+				t.Fatalf("encoded as %v (set: %t), want %v", v, ok, want)
+			}
+			if !ok {
+				return
+			}
+
+			got := decode(t, v, reflect.TypeOf(want))
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("decoded %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestUnsupportedKind(t *testing.T) {
+	type item struct {
+		C chan int
+	}
+	if _, err := fieldsOf("", reflect.TypeFor[item]()); err == nil {
+		t.Error("expected an error for a channel field")
+	}
+}
+
+func TestCoercionFallback(t *testing.T) {
+	type item struct {
+		LanIP string
+	}
+	columns, err := columnsFor("coercion_test", reflect.TypeFor[item](), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := &v1.Table{Name: "coercion_test", Columns: columns}
+	idx, err := indexFor(table, reflect.TypeFor[item](), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	row, err := columnValuesFor(table, idx, item{LanIP: "not an address"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row[0].DataType.GetInet() == nil {
+		t.Errorf("fallback type = %s, want inet", TypeName(row[0].DataType))
+	}
+	if v, ok := unionValue(row[0].DataType); ok {
+		t.Errorf("fallback value = %v, want null", v)
+	}
+	if got := row[1].DataType.GetText().GetValue(); row[1].Name != "lan_ip_raw" || got != "not an address" {
+		t.Errorf("raw column %s = %q, want lan_ip_raw with the original string", row[1].Name, got)
+	}
+
+	row, err = columnValuesFor(table, idx, item{LanIP: "10.0.0.1"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := unionValue(row[1].DataType); ok {
+		t.Errorf("raw value of a valid address = %v, want null", v)
+	}
+	if n := CoercionFailures()["coercion_test"]; n != 1 {
+		t.Errorf("coercion failures = %d, want 1", n)
+	}
+}
+
+func TestPrimaryKeyNotNillable(t *testing.T) {
+	type item struct {
+		Ts     string
+		Number *int
+	}
+	columns, err := columnsFor("key_test", reflect.TypeFor[item](), []string{"ts", "number"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range columns {
+		if c.PrimaryKey && c.Nillable {
+			t.Errorf("primary key column %q is nillable", c.Name)
+		}
+	}
+	table := &v1.Table{Name: "key_test", Columns: columns}
+	idx, err := indexFor(table, reflect.TypeFor[item](), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, v := range map[string]item{
+		"empty ts":   {Number: ptr(1)},
+		"nil number": {Ts: "2024-01-01T00:00:00Z"},
+		"invalid ts": {Ts: "yesterday", Number: ptr(1)},
+	} {
+		if _, err := columnValuesFor(table, idx, v, nil); err == nil {
+			t.Errorf("%s: encoded a null primary key", name)
+		}
+	}
+
+	rc := &Resource{Table: &v1.Table{Name: "key_test", Columns: []*v1.Column{
+		{Name: "id", PrimaryKey: true, Nillable: true, DataType: &v1.DataType{Union: _Text}},
+	}}}
+	if err := Validate(rc); err == nil {
+		t.Error("Validate accepted a nillable primary key column")
+	}
+}
+
+func TestFlattenDepth(t *testing.T) {
+	type usage struct {
+		Sent *float64
+		Recv *float64
+	}
+	type item struct {
+		ID    string
+		Usage *usage
+	}
+	names := func() []string {
+		t.Helper()
+		columns, err := columnsFor("flatten_test", reflect.TypeFor[item](), []string{"id"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, c := range columns {
+			names = append(names, c.Name+" "+TypeName(c.DataType))
+		}
+		return names
+	}
+
+	if got, want := names(), []string{"id text", "usage jsonb"}; !slices.Equal(got, want) {
+		t.Errorf("columns = %q, want %q", got, want)
+	}
+
+	if err := SetFlattenDepth("flatten_test", 1); err != nil {
+		t.Fatal(err)
+	}
+	defer delete(flattenDepth, "flatten_test")
+	if got, want := names(), []string{"id text", "usage_sent double", "usage_recv double"}; !slices.Equal(got, want) {
+		t.Errorf("flattened columns = %q, want %q", got, want)
+	}
+
+	if err := SetFlattenDepth("flatten_test", -1); err == nil {
+		t.Error("SetFlattenDepth accepted a negative depth")
+	}
+}
+
+// unionValue returns the value set in dt's union, if any.
+func unionValue(dt *v1.DataType) (protoreflect.Value, bool) {
+	m := dt.ProtoReflect()
+	fd := m.WhichOneof(m.Descriptor().Oneofs().ByName("union"))
+	if fd == nil {
+		return protoreflect.Value{}, false
+	}
+	inner := m.Get(fd).Message()
+	vf := inner.Descriptor().Fields().ByName("value")
+	if !inner.Has(vf) {
+		return protoreflect.Value{}, false
+	}
+	return inner.Get(vf), true
+}
+
+// decode converts an encoded value back into a value of type typ.
+func decode(t *testing.T, v protoreflect.Value, typ reflect.Type) any {
+	t.Helper()
+
+	if typ.Kind() == reflect.Pointer {
+		p := reflect.New(typ.Elem())
+		p.Elem().Set(reflect.ValueOf(decode(t, v, typ.Elem())))
+		return p.Interface()
+	}
+
+	out := reflect.New(typ).Elem()
+	var err error
+	switch typ {
+	case reflect.TypeFor[time.Time]():
+		var ts time.Time
+		ts, err = time.Parse(pgTstzFmt, v.String())
+		out.Set(reflect.ValueOf(ts))
+	case reflect.TypeFor[net.IP]():
+		out.Set(reflect.ValueOf(net.ParseIP(v.String())))
+	case reflect.TypeFor[net.IPAddr]():
+		out.Set(reflect.ValueOf(net.IPAddr{IP: net.ParseIP(v.String())}))
+	case reflect.TypeFor[netip.Addr]():
+		var a netip.Addr
+		a, err = netip.ParseAddr(v.String())
+		out.Set(reflect.ValueOf(a))
+	case reflect.TypeFor[net.IPNet]():
+		var n *net.IPNet
+		if _, n, err = net.ParseCIDR(v.String()); err == nil {
+			out.Set(reflect.ValueOf(*n))
+		}
+	case reflect.TypeFor[netip.Prefix]():
+		var p netip.Prefix
+		p, err = netip.ParsePrefix(v.String())
+		out.Set(reflect.ValueOf(p))
+	case reflect.TypeFor[net.HardwareAddr]():
+		var mac net.HardwareAddr
+		mac, err = net.ParseMAC(v.String())
+		out.Set(reflect.ValueOf(mac))
+	case reflect.TypeFor[bytes.Buffer]():
+		out.Set(reflect.ValueOf(*bytes.NewBuffer(v.Bytes())))
+	default:
+		switch typ.Kind() {
+		case reflect.String:
+			out.SetString(v.String())
+		case reflect.Bool:
+			out.SetBool(v.Bool())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if s, ok := v.Interface().(string); ok {
+				var i int64
+				i, err = strconv.ParseInt(s, 10, 64)
+				out.SetInt(i)
+			} else {
+				out.SetInt(v.Int())
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			out.SetUint(uint64(v.Int()))
+		case reflect.Float32, reflect.Float64:
+			out.SetFloat(v.Float())
+		case reflect.Complex64, reflect.Complex128:
+			var c complex128
+			c, err = strconv.ParseComplex(v.String(), 128)
+			out.SetComplex(c)
+		case reflect.Slice, reflect.Array:
+			if isByte(typ.Elem()) && v.Interface() != nil {
+				if b, ok := v.Interface().([]byte); ok {
+					if typ.Kind() == reflect.Slice {
+						out.Set(reflect.MakeSlice(typ, len(b), len(b)))
+					}
+					for i, c := range b {
+						if e := out.Index(i); e.CanUint() {
+							e.SetUint(uint64(c))
+						} else {
+							e.SetInt(int64(int8(c)))
+						}
+					}
+					break
+				}
+			}
+			err = json.Unmarshal([]byte(v.String()), out.Addr().Interface())
+		default:
+			err = json.Unmarshal([]byte(v.String()), out.Addr().Interface())
+		}
+	}
+	if err != nil {
+		t.Fatalf("failed to decode %v as %s: %s", v, typ, err)
+	}
+	return out.Interface()
+}
@@ -19,50 +19,48 @@ package resource
 
 import (
 	"context"
-	"errors"
-	"fmt"
 	"iter"
-	"log"
 
+	"github.com/go-resty/resty/v2"
 	meraki "github.com/meraki/dashboard-api-go/v4/sdk"
 	v1 "github.com/secberus/go-push-api/types/v1"
 )
 
-var Networks = &Resource{
+var Networks = &Of[merakiOrganization, meraki.ResponseItemOrganizationsGetOrganizationNetworks]{
 	Table: &v1.Table{
 		Name:     "meraki_networks",
 		SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
-		Columns:  columnsFor[meraki.ResponseItemOrganizationsGetOrganizationNetworks]("id"),
 	},
-	Resolver: getOrganizationNetworks,
-	Children: []*Resource{
+	PrimaryKey: []string{"id"},
+	Resolver:   getOrganizationNetworks,
+	Key: Key[merakiNetwork]{
+		Column: "network_id",
+		Value:  func(n merakiNetwork) string { return n.ID },
+	},
+	Children: []Child[merakiNetwork]{
 		Devices,
 		TopologyLinkLayer,
+		NetworkTags,
+		NetworkProductTypes,
+		ApplianceL3FirewallRules,
+		ApplianceL7FirewallRules,
+		ApplianceInboundFirewallRules,
+		AppliancePortForwardingRules,
+		ApplianceOneToOneNatRules,
+		ApplianceOneToManyNatRules,
+		WirelessSSIDs,
 	},
 }
 
-func getOrganizationNetworks(ctx context.Context, client *meraki.Client, org any) iter.Seq2[any, error] {
-	orgId := org.(meraki.ResponseItemOrganizationsGetOrganizations).ID
-	return func(yield func(any, error) bool) {
-		rsl, rsp, err := client.Organizations.GetOrganizationNetworks(orgId, &meraki.GetOrganizationNetworksQueryParams{PerPage: -1})
-		if err != nil {
-			if rsp != nil && rsp.IsError() {
-				log.Printf("rsp status: %s, error: %+v\n", rsp.Status(), rsp.Error())
-				if err2, ok := rsp.Error().(error); ok {
-					err = errors.Join(err, err2)
-				}
-			}
-			yield(nil, fmt.Errorf("failed to GetOrganizationNetworks: %w", err))
-			return
-		}
-		if rsl == nil {
-			yield(nil, errors.New("received nil response from GetOrganizationNetworks"))
-			return
-		}
-		for _, i := range *rsl {
-			if !yield(i, nil) {
-				return
-			}
-		}
-	}
+var NetworkTags = explode("meraki_network_tags", func(n merakiNetwork) []string { return n.Tags }, "network_id")
+
+var NetworkProductTypes = explode("meraki_network_product_types", func(n merakiNetwork) []string { return n.ProductTypes }, "network_id")
+
+func getOrganizationNetworks(ctx context.Context, client *meraki.Client, org merakiOrganization) iter.Seq2[merakiNetwork, error] {
+	return paginate("GetOrganizationNetworks", func(startingAfter string) (*meraki.ResponseOrganizationsGetOrganizationNetworks, *resty.Response, error) {
+		return client.Organizations.GetOrganizationNetworks(org.ID, &meraki.GetOrganizationNetworksQueryParams{
+			PerPage:       meraki.PAGINATION_PER_PAGE,
+			StartingAfter: startingAfter,
+		})
+	})
 }
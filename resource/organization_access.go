@@ -0,0 +1,102 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package resource
+
+import (
+	"context"
+	"iter"
+
+	"github.com/go-resty/resty/v2"
+	meraki "github.com/meraki/dashboard-api-go/v4/sdk"
+	v1 "github.com/secberus/go-push-api/types/v1"
+)
+
+var OrganizationAdmins = &Of[merakiOrganization, meraki.ResponseItemOrganizationsGetOrganizationAdmins]{
+	Table: &v1.Table{
+		Name:     "meraki_organization_admins",
+		SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
+	},
+	PrimaryKey: []string{"organization_id", "id"},
+	Resolver:   getOrganizationAdmins,
+}
+
+func getOrganizationAdmins(_ context.Context, client *meraki.Client, org merakiOrganization) iter.Seq2[meraki.ResponseItemOrganizationsGetOrganizationAdmins, error] {
+	return list("GetOrganizationAdmins", func() (*meraki.ResponseOrganizationsGetOrganizationAdmins, *resty.Response, error) {
+		return client.Organizations.GetOrganizationAdmins(org.ID, nil)
+	})
+}
+
+var OrganizationSamlRoles = &Of[merakiOrganization, meraki.ResponseItemOrganizationsGetOrganizationSamlRoles]{
+	Table: &v1.Table{
+		Name:     "meraki_organization_saml_roles",
+		SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
+	},
+	PrimaryKey: []string{"organization_id", "id"},
+	Resolver:   getOrganizationSamlRoles,
+}
+
+func getOrganizationSamlRoles(_ context.Context, client *meraki.Client, org merakiOrganization) iter.Seq2[meraki.ResponseItemOrganizationsGetOrganizationSamlRoles, error] {
+	return list("GetOrganizationSamlRoles", func() (*meraki.ResponseOrganizationsGetOrganizationSamlRoles, *resty.Response, error) {
+		return client.Organizations.GetOrganizationSamlRoles(org.ID)
+	})
+}
+
+var OrganizationSaml = &Of[merakiOrganization, meraki.ResponseOrganizationsGetOrganizationSaml]{
+	Table: &v1.Table{
+		Name:     "meraki_organization_saml",
+		SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
+	},
+	PrimaryKey: []string{"organization_id"},
+	Resolver:   getOrganizationSaml,
+}
+
+func getOrganizationSaml(_ context.Context, client *meraki.Client, org merakiOrganization) iter.Seq2[meraki.ResponseOrganizationsGetOrganizationSaml, error] {
+	return single("GetOrganizationSaml", func() (*meraki.ResponseOrganizationsGetOrganizationSaml, *resty.Response, error) {
+		return client.Organizations.GetOrganizationSaml(org.ID)
+	})
+}
+
+var OrganizationSamlIdps = &Of[merakiOrganization, meraki.ResponseItemOrganizationsGetOrganizationSamlIDps]{
+	Table: &v1.Table{
+		Name:     "meraki_organization_saml_idps",
+		SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
+	},
+	PrimaryKey: []string{"organization_id", "idp_id"},
+	Resolver:   getOrganizationSamlIdps,
+}
+
+func getOrganizationSamlIdps(_ context.Context, client *meraki.Client, org merakiOrganization) iter.Seq2[meraki.ResponseItemOrganizationsGetOrganizationSamlIDps, error] {
+	return list("GetOrganizationSamlIDps", func() (*meraki.ResponseOrganizationsGetOrganizationSamlIDps, *resty.Response, error) {
+		return client.Organizations.GetOrganizationSamlIDps(org.ID)
+	})
+}
+
+var OrganizationLoginSecurity = &Of[merakiOrganization, meraki.ResponseOrganizationsGetOrganizationLoginSecurity]{
+	Table: &v1.Table{
+		Name:     "meraki_organization_login_security",
+		SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
+	},
+	PrimaryKey: []string{"organization_id"},
+	Resolver:   getOrganizationLoginSecurity,
+}
+
+func getOrganizationLoginSecurity(_ context.Context, client *meraki.Client, org merakiOrganization) iter.Seq2[meraki.ResponseOrganizationsGetOrganizationLoginSecurity, error] {
+	return single("GetOrganizationLoginSecurity", func() (*meraki.ResponseOrganizationsGetOrganizationLoginSecurity, *resty.Response, error) {
+		return client.Organizations.GetOrganizationLoginSecurity(org.ID)
+	})
+}
@@ -19,49 +19,43 @@ package resource
 
 import (
 	"context"
-	"errors"
-	"fmt"
 	"iter"
-	"log"
 
+	"github.com/go-resty/resty/v2"
 	meraki "github.com/meraki/dashboard-api-go/v4/sdk"
 	v1 "github.com/secberus/go-push-api/types/v1"
 )
 
-var Organizations = &Resource{
+var Organizations = &Of[any, merakiOrganization]{
 	Table: &v1.Table{
 		Name:     "meraki_organizations",
 		SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
-		Columns:  columnsFor[meraki.ResponseItemOrganizationsGetOrganizations]("id"),
 	},
-	Resolver: getOrganizations,
-	Children: []*Resource{
+	PrimaryKey: []string{"id"},
+	Resolver:   getOrganizations,
+	Key: Key[merakiOrganization]{
+		Column: "organization_id",
+		Value:  func(o merakiOrganization) string { return o.ID },
+	},
+	Children: []Child[merakiOrganization]{
 		Networks,
 		ConfigurationChanges,
+		OrganizationAdmins,
+		OrganizationSamlRoles,
+		OrganizationSaml,
+		OrganizationSamlIdps,
+		OrganizationLoginSecurity,
+		InventoryDevices,
+		LicensesOverview,
+		Licenses,
 	},
 }
 
-func getOrganizations(ctx context.Context, client *meraki.Client, _ any) iter.Seq2[any, error] {
-	return func(yield func(any, error) bool) {
-		rsl, rsp, err := client.Organizations.GetOrganizations(&meraki.GetOrganizationsQueryParams{PerPage: -1})
-		if err != nil {
-			if rsp != nil && rsp.IsError() {
-				log.Printf("rsp status: %s, error: %+v\n", rsp.Status(), rsp.Error())
-				if err2, ok := rsp.Error().(error); ok {
-					err = errors.Join(err, err2)
-				}
-			}
-			yield(nil, fmt.Errorf("failed to GetOrganizations: %w", err))
-			return
-		}
-		if rsl == nil {
-			yield(nil, errors.New("received nil response from GetOrganizations"))
-			return
-		}
-		for _, i := range *rsl {
-			if !yield(i, nil) {
-				return
-			}
-		}
-	}
+func getOrganizations(ctx context.Context, client *meraki.Client, _ any) iter.Seq2[merakiOrganization, error] {
+	return paginate("GetOrganizations", func(startingAfter string) (*meraki.ResponseOrganizationsGetOrganizations, *resty.Response, error) {
+		return client.Organizations.GetOrganizations(&meraki.GetOrganizationsQueryParams{
+			PerPage:       meraki.PAGINATION_PER_PAGE,
+			StartingAfter: startingAfter,
+		})
+	})
 }
@@ -0,0 +1,124 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package resource
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"log"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// paginate yields the items of every page of a list endpoint, following the
+// Link header of each response until there is no next page. fetch is passed
+// an empty startingAfter for the first page; endpoints without pagination may
+// ignore it. Only a single page is held in memory at a time.
+func paginate[S ~[]T, T any](op string, fetch func(startingAfter string) (*S, *resty.Response, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var next string
+		for {
+			rsl, rsp, err := fetch(next)
+			if err != nil {
+				yield(zero[T](), apiError(op, rsp, err))
+				return
+			}
+			if rsl == nil {
+				yield(zero[T](), fmt.Errorf("received nil response from %s", op))
+				return
+			}
+			for _, i := range *rsl {
+				if !yield(i, nil) {
+					return
+				}
+			}
+
+			var ok bool
+			if next, ok = startingAfter(rsp); !ok {
+				return
+			}
+		}
+	}
+}
+
+// single yields the object returned by an endpoint that is not a list, such
+// as a settings endpoint, as the only item.
+func single[T any](op string, fetch func() (*T, *resty.Response, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		rsl, rsp, err := fetch()
+		if err != nil {
+			yield(zero[T](), apiError(op, rsp, err))
+			return
+		}
+		if rsl == nil {
+			yield(zero[T](), fmt.Errorf("received nil response from %s", op))
+			return
+		}
+		yield(*rsl, nil)
+	}
+}
+
+// list yields the items of a list endpoint that is not paginated, which
+// returns every item in a single response.
+func list[S ~[]T, T any](op string, fetch func() (*S, *resty.Response, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for rsl, err := range single(op, fetch) {
+			if err != nil {
+				yield(zero[T](), err)
+				return
+			}
+			for _, i := range rsl {
+				if !yield(i, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// items adapts a typed sequence to the Resolver contract.
+func items[T any](seq iter.Seq2[T, error]) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		for v, err := range seq {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+	}
+}
+
+func fail(err error) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		yield(nil, err)
+	}
+}
+
+func apiError(op string, rsp *resty.Response, err error) error {
+	if rsp != nil && rsp.IsError() {
+		log.Printf("rsp status: %s, error: %+v\n", rsp.Status(), rsp.Error())
+		if err2, ok := rsp.Error().(error); ok {
+			err = errors.Join(err, err2)
+		}
+	}
+	return fmt.Errorf("failed to %s: %w", op, err)
+}
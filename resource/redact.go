@@ -0,0 +1,65 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package resource
+
+import (
+	"reflect"
+	"slices"
+)
+
+// sensitiveFields are the names of string fields whose values are never
+// collected, such as RADIUS shared secrets and pre-shared keys. The Meraki
+// SDK does not currently model them, but may in later versions.
+var sensitiveFields = []string{"Secret", "Psk", "PSK", "Password", "Passphrase", "SharedSecret"}
+
+const redacted = "[REDACTED]"
+
+// redact replaces the non-empty values of sensitive string fields reachable
+// from v, which must be a pointer, with a placeholder, so that it is still
+// visible whether they are set.
+func redact(v any) {
+	redactValue(reflect.ValueOf(v))
+}
+
+func redactValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			redactValue(v.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			redactValue(v.Index(i))
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := v.Field(i)
+			if !f.CanSet() {
+				continue
+			}
+			if f.Kind() == reflect.String && slices.Contains(sensitiveFields, t.Field(i).Name) {
+				if f.String() != "" {
+					f.SetString(redacted)
+				}
+				continue
+			}
+			redactValue(f)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package resource
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+
+	"github.com/go-resty/resty/v2"
+	meraki "github.com/meraki/dashboard-api-go/v4/sdk"
+	v1 "github.com/secberus/go-push-api/types/v1"
+)
+
+// ruleList returns a resource with a row per rule in the rule list of each
+// parent, numbered by an ordinal column holding the position of the rule,
+// which is its order of evaluation. fetch is not called for parents that
+// skip returns true for. pk names the parent key columns that identify a row
+// together with its ordinal. The rules of a parent are replaced on every
+// run, so that removed rules do not linger and ordinals follow the order.
+func ruleList[P, R, E any](table, op string, skip func(P) bool, fetch func(*meraki.Client, P) (*R, *resty.Response, error), rules func(*R) *[]E, pk ...string) *Of[P, element[E]] {
+	return &Of[P, element[E]]{
+		Table: &v1.Table{
+			Name:     table,
+			SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
+		},
+		PrimaryKey: pk,
+		Replace:    true,
+		Resolver: func(_ context.Context, client *meraki.Client, parent P) iter.Seq2[element[E], error] {
+			return func(yield func(element[E], error) bool) {
+				if skip != nil && skip(parent) {
+					return
+				}
+				rsl, rsp, err := fetch(client, parent)
+				if err != nil {
+					yield(element[E]{}, apiError(op, rsp, err))
+					return
+				}
+				if rsl == nil {
+					yield(element[E]{}, fmt.Errorf("received nil response from %s", op))
+					return
+				}
+				for i, r := range deref(rules(rsl)) {
+					if !yield(element[E]{Ordinal: i, Value: r}, nil) {
+						return
+					}
+				}
+			}
+		},
+	}
+}
+
+// lacksProductType returns a skip function for networks that do not support
+// productType, e.g. "appliance", whose endpoints would fail with 400.
+func lacksProductType(productType string) func(merakiNetwork) bool {
+	return func(n merakiNetwork) bool {
+		return !slices.Contains(n.ProductTypes, productType)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package resource
+
+import (
+	"context"
+	"iter"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	meraki "github.com/meraki/dashboard-api-go/v4/sdk"
+	v1 "github.com/secberus/go-push-api/types/v1"
+)
+
+var SwitchPorts = &Of[merakiDevice, meraki.ResponseItemSwitchGetDeviceSwitchPorts]{
+	Table: &v1.Table{
+		Name:     "meraki_switch_ports",
+		SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
+	},
+	PrimaryKey: []string{"device_serial", "port_id"},
+	Resolver:   getDeviceSwitchPorts,
+}
+
+func getDeviceSwitchPorts(_ context.Context, client *meraki.Client, device merakiDevice) iter.Seq2[meraki.ResponseItemSwitchGetDeviceSwitchPorts, error] {
+	if !isSwitch(device) {
+		return func(func(meraki.ResponseItemSwitchGetDeviceSwitchPorts, error) bool) {}
+	}
+	return list("GetDeviceSwitchPorts", func() (*meraki.ResponseSwitchGetDeviceSwitchPorts, *resty.Response, error) {
+		return client.Switch.GetDeviceSwitchPorts(device.Serial)
+	})
+}
+
+var SwitchPortStatuses = &Of[merakiDevice, meraki.ResponseItemSwitchGetDeviceSwitchPortsStatuses]{
+	Table: &v1.Table{
+		Name:     "meraki_switch_port_statuses",
+		SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
+	},
+	PrimaryKey: []string{"device_serial", "port_id"},
+	Resolver:   getDeviceSwitchPortsStatuses,
+}
+
+func getDeviceSwitchPortsStatuses(_ context.Context, client *meraki.Client, device merakiDevice) iter.Seq2[meraki.ResponseItemSwitchGetDeviceSwitchPortsStatuses, error] {
+	if !isSwitch(device) {
+		return func(func(meraki.ResponseItemSwitchGetDeviceSwitchPortsStatuses, error) bool) {}
+	}
+	return list("GetDeviceSwitchPortsStatuses", func() (*meraki.ResponseSwitchGetDeviceSwitchPortsStatuses, *resty.Response, error) {
+		return client.Switch.GetDeviceSwitchPortsStatuses(device.Serial, nil)
+	})
+}
+
+// switchModels are the model prefixes of Meraki MS and cloud managed
+// Catalyst switches. The switch endpoints fail with 400 for other devices.
+var switchModels = []string{"MS", "C9"}
+
+func isSwitch(device merakiDevice) bool {
+	for _, prefix := range switchModels {
+		if strings.HasPrefix(device.Model, prefix) {
+			return true
+		}
+	}
+	return false
+}
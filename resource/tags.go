@@ -0,0 +1,254 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package resource
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	v1 "github.com/secberus/go-push-api/types/v1"
+)
+
+// columnOverrides maps table names to per-field column overrides for item
+// types that cannot carry s6s struct tags, such as those of the Meraki SDK.
+// Values use the struct tag syntax and replace any tag on the field.
+var columnOverrides = map[string]map[string]string{
+	"meraki_network_tags": {
+		"Value": "tag",
+	},
+	"meraki_network_product_types": {
+		"Value": "product_type",
+	},
+	"meraki_licenses": {
+		"ActivationDate": "activation_date,type=timestamptz",
+		"ClaimDate":      "claim_date,type=timestamptz",
+		"ExpirationDate": "expiration_date,type=timestamptz",
+	},
+	"meraki_inventory_devices": {
+		"LicenseExpirationDate": "license_expiration_date,type=timestamptz",
+	},
+}
+
+// columnTag is the parsed form of an `s6s:"name,type=inet,skip,pk,inline"`
+// struct tag or column override. Every part is optional. Inline struct fields
+// are expanded into unprefixed columns regardless of flattening.
+type columnTag struct {
+	Name   string
+	Type   *v1.DataType
+	Skip   bool
+	PK     bool
+	Inline bool
+}
+
+var dataTypes = map[string]*v1.DataType{
+	"text":        {Union: _Text},
+	"boolean":     {Union: _Boolean},
+	"integer":     {Union: _Integer},
+	"smallint":    {Union: _Smallint},
+	"bigint":      {Union: _Bigint},
+	"real":        {Union: _Real},
+	"double":      {Union: _Double},
+	"bytea":       {Union: _Bytea},
+	"timestamptz": {Union: _Timestamptz},
+	"jsonb":       {Union: _Jsonb},
+	"inet":        {Union: _Inet},
+	"cidr":        {Union: _Cidr},
+	"macaddr":     {Union: _Macaddr},
+}
+
+func parseColumnTag(s string) (columnTag, error) {
+	var tag columnTag
+	if s == "-" {
+		tag.Skip = true
+		return tag, nil
+	}
+
+	name, opts, _ := strings.Cut(s, ",")
+	tag.Name = name
+	if opts == "" {
+		return tag, nil
+	}
+	for _, opt := range strings.Split(opts, ",") {
+		switch k, v, _ := strings.Cut(opt, "="); k {
+		case "skip":
+			tag.Skip = true
+		case "pk":
+			tag.PK = true
+		case "inline":
+			tag.Inline = true
+		case "type":
+			dt, ok := dataTypes[v]
+			if !ok {
+				return tag, fmt.Errorf("unknown column type %q", v)
+			}
+			tag.Type = dt
+		default:
+			return tag, fmt.Errorf("unknown option %q", opt)
+		}
+	}
+	return tag, nil
+}
+
+// flattenDepth opts tables in to expanding nested structs of their items into
+// prefixed columns, e.g. usage_sent and usage_recv, down to the given depth.
+// Deeper structs remain jsonb columns. The defaults cover tables that were
+// flattened from the start; others are opted in by SetFlattenDepth.
+var flattenDepth = map[string]int{
+	"meraki_organization_login_security": 2,
+	"meraki_licenses_overview":           2,
+}
+
+// SetFlattenDepth sets the depth to which nested structs of the items of
+// table are expanded into columns, 0 keeping them as jsonb. It must be called
+// before the resources of the table are built.
+func SetFlattenDepth(table string, depth int) error {
+	if depth < 0 {
+		return fmt.Errorf("invalid flatten depth %d for table %q", depth, table)
+	}
+	flattenDepth[table] = depth
+	return nil
+}
+
+// field is a column of a table populated from the field at path of its items.
+type field struct {
+	path    []int
+	column  *v1.Column
+	coerced bool
+	// raw is set for the text column that keeps the values of a coerced
+	// column that fail to parse as its type.
+	raw *v1.DataType
+}
+
+// fieldsOf returns the field columns of table for items of type t, following
+// s6s struct tags, column overrides and flattening. Invalid tags are ignored,
+// but reported by the returned error.
+func fieldsOf(table string, t reflect.Type) ([]field, error) {
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	var fields []field
+	var errs []error
+	seen := make(map[string]bool)
+
+	var walk func(t reflect.Type, path []int, key, prefix string, depth int, nillable bool)
+	walk = func(t reflect.Type, path []int, key, prefix string, depth int, nillable bool) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			fkey := key + f.Name
+			seen[fkey] = true
+
+			tag, err := fieldTag(table, fkey, f)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			if tag.Skip {
+				continue
+			}
+			fpath := append(path[:len(path):len(path)], i)
+
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if tag.Inline && tag.Type == nil && flattens(ft) {
+				walk(ft, fpath, fkey+".", prefix, depth, nillable || f.Type.Kind() == reflect.Pointer)
+				continue
+			}
+
+			tag.Name = prefix + columnName(f, tag)
+			if depth < flattenDepth[table] && tag.Type == nil && flattens(ft) {
+				walk(ft, fpath, fkey+".", tag.Name+"_", depth+1, nillable || f.Type.Kind() == reflect.Pointer)
+				continue
+			}
+
+			c, err := columnFor(f, tag)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			c.Nillable = c.Nillable || nillable
+			coerced := tag.Type == nil && ft.Kind() == reflect.String && coercionFor(c.Name) != nil
+			fields = append(fields, field{
+				path:    fpath,
+				column:  c,
+				coerced: coerced,
+			})
+			if coerced {
+				fields = append(fields, field{
+					path: fpath,
+					column: &v1.Column{
+						Name:     c.Name + "_raw",
+						DataType: &v1.DataType{Union: _Text},
+						Nillable: true,
+					},
+					raw: c.DataType,
+				})
+			}
+		}
+	}
+	walk(t, nil, "", "", 0, false)
+
+	for key := range columnOverrides[table] {
+		if !seen[key] {
+			errs = append(errs, fmt.Errorf("column override for unknown field %q of %s", key, t))
+		}
+	}
+	return fields, errors.Join(errs...)
+}
+
+// flattens reports whether fields of type t may be expanded into columns.
+func flattens(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && typeColumns[t] == nil
+}
+
+// fieldTag returns the column tag of field f, at the dotted path key of the
+// items of table, taking overrides over struct tags.
+func fieldTag(table, key string, f reflect.StructField) (columnTag, error) {
+	s, ok := columnOverrides[table][key]
+	if !ok {
+		s = f.Tag.Get("s6s")
+	}
+	tag, err := parseColumnTag(s)
+	if err != nil {
+		return tag, fmt.Errorf("invalid column tag for field %q: %w", key, err)
+	}
+	return tag, nil
+}
+
+func columnName(f reflect.StructField, tag columnTag) string {
+	if tag.Name != "" {
+		return tag.Name
+	}
+	return snakecase(f.Name)
+}
+
+// fieldsByName indexes the field columns of table by column name.
+func fieldsByName(table string, t reflect.Type) map[string]field {
+	fields, _ := fieldsOf(table, t)
+	m := make(map[string]field, len(fields))
+	for _, f := range fields {
+		m[f.column.Name] = f
+	}
+	return m
+}
@@ -20,46 +20,49 @@ package resource
 import (
 	"context"
 	"errors"
-	"fmt"
 	"iter"
-	"log"
 
 	meraki "github.com/meraki/dashboard-api-go/v4/sdk"
 	v1 "github.com/secberus/go-push-api/types/v1"
 )
 
-var TopologyLinkLayer = &Resource{
+var TopologyLinkLayer = &Of[merakiNetwork, topologyLinkLayer]{
 	Table: &v1.Table{
 		Name:     "meraki_topology_link_layers",
 		SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
-		Columns:  columnsFor[topologyLinkLayer]("network_id"),
 	},
 	Resolver: getTopologyLinkLayer,
+	Children: []Child[topologyLinkLayer]{
+		TopologyLinks,
+		TopologyNodes,
+	},
 }
 
+var TopologyLinks = explode("meraki_topology_links", func(l topologyLinkLayer) []meraki.ResponseNetworksGetNetworkTopologyLinkLayerLinks {
+	return deref(l.Links)
+}, "network_id")
+
+var TopologyNodes = explode("meraki_topology_nodes", func(l topologyLinkLayer) []meraki.ResponseNetworksGetNetworkTopologyLinkLayerNodes {
+	return deref(l.Nodes)
+}, "network_id")
+
 type topologyLinkLayer struct {
-	NetworkId string
+	NetworkId string `s6s:"network_id,pk"`
 	Errors    []string
 	Links     *[]meraki.ResponseNetworksGetNetworkTopologyLinkLayerLinks
 	Nodes     *[]meraki.ResponseNetworksGetNetworkTopologyLinkLayerNodes
 }
 
-func getTopologyLinkLayer(ctx context.Context, client *meraki.Client, network any) iter.Seq2[any, error] {
-	networkId := network.(meraki.ResponseItemOrganizationsGetOrganizationNetworks).ID
-	return func(yield func(any, error) bool) {
+func getTopologyLinkLayer(ctx context.Context, client *meraki.Client, network merakiNetwork) iter.Seq2[topologyLinkLayer, error] {
+	networkId := network.ID
+	return func(yield func(topologyLinkLayer, error) bool) {
 		rsl, rsp, err := client.Networks.GetNetworkTopologyLinkLayer(networkId)
 		if err != nil {
-			if rsp != nil && rsp.IsError() {
-				log.Printf("rsp status: %s, error: %+v\n", rsp.Status(), rsp.Error())
-				if err2, ok := rsp.Error().(error); ok {
-					err = errors.Join(err, err2)
-				}
-			}
-			yield(nil, fmt.Errorf("failed to GetNetworkTopologyLinkLayer: %w", err))
+			yield(topologyLinkLayer{}, apiError("GetNetworkTopologyLinkLayer", rsp, err))
 			return
 		}
 		if rsl == nil {
-			yield(nil, errors.New("received nil response from GetNetworkTopologyLinkLayer"))
+			yield(topologyLinkLayer{}, errors.New("received nil response from GetNetworkTopologyLinkLayer"))
 			return
 		}
 		yield(topologyLinkLayer{
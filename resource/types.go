@@ -19,16 +19,216 @@ package resource
 
 import (
 	"context"
+	"fmt"
 	"iter"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
 
 	meraki "github.com/meraki/dashboard-api-go/v4/sdk"
 	v1 "github.com/secberus/go-push-api/types/v1"
 )
 
+// State is persisted between runs for a single table and parent, so that
+// resolvers can collect incrementally. Changes made during a run are only
+// saved once the records yielded by the resolver have been upserted.
+type State interface {
+	Cursor() string
+	SetCursor(string)
+	ETag() string
+	SetETag(string)
+	// LastRun is the start of the last successful run, or the zero time.
+	LastRun() time.Time
+}
+
+type stateKey struct{}
+
+func WithState(ctx context.Context, s State) context.Context {
+	return context.WithValue(ctx, stateKey{}, s)
+}
+
+// StateFrom returns the State of the current resolver, or a State that
+// discards all values if there is none.
+func StateFrom(ctx context.Context) State {
+	if s, ok := ctx.Value(stateKey{}).(State); ok {
+		return s
+	}
+	return discardState{}
+}
+
+type discardState struct{}
+
+func (discardState) Cursor() string     { return "" }
+func (discardState) SetCursor(string)   {}
+func (discardState) ETag() string       { return "" }
+func (discardState) SetETag(string)     {}
+func (discardState) LastRun() time.Time { return time.Time{} }
+
 type Resolver func(context.Context, *meraki.Client, any) iter.Seq2[any, error]
 
+// parent types shared by the resources below them
+type (
+	merakiOrganization = meraki.ResponseItemOrganizationsGetOrganizations
+	merakiNetwork      = meraki.ResponseItemOrganizationsGetOrganizationNetworks
+	merakiDevice       = meraki.ResponseItemNetworksGetNetworkDevices
+)
+
+// Resource is a node of the heterogeneous resource tree walked by the
+// Collector. Resources are normally built from an Of by its Resource method,
+// which also records the parent and item types used by Validate.
 type Resource struct {
 	Table    *v1.Table
 	Resolver Resolver
 	Children []*Resource
+
+	Parent reflect.Type
+	Item   reflect.Type
+
+	// KeyColumn is the column that the items of this resource contribute to
+	// the tables of its descendants, with the value given by KeyValue.
+	KeyColumn string
+	KeyValue  func(any) string
+	// Inherited are the parent key columns appended to Table.
+	Inherited []string
+	// Replace deletes the rows of each parent before its items are upserted,
+	// for tables whose rows are positional, such as the elements of a list,
+	// so that removed elements do not linger.
+	Replace bool
+
+	columnsErr error
+	indexOnce  sync.Once
+	index      *columnIndex
+	indexErr   error
+}
+
+// columnIndex returns the index of the columns of rc, which is built once
+// the tree is complete and all parent key columns are known.
+func (rc *Resource) columnIndex() (*columnIndex, error) {
+	rc.indexOnce.Do(func() {
+		rc.index, rc.indexErr = indexFor(rc.Table, rc.Item, rc.Inherited)
+	})
+	return rc.index, rc.indexErr
+}
+
+// inherit appends the parent key column to the tables of rc and all of its
+// descendants. A table that already declares the column as a primary key
+// that no field maps to gets its value from the parent, while one with a
+// field of that name keeps the field.
+func inherit(rc *Resource, column string) {
+	i := slices.IndexFunc(rc.Table.Columns, func(c *v1.Column) bool { return c != nil && c.Name == column })
+	if i < 0 {
+		rc.Table.Columns = append(rc.Table.Columns, &v1.Column{
+			Name:     column,
+			DataType: &v1.DataType{Union: _Text},
+		})
+		rc.Inherited = append(rc.Inherited, column)
+	} else if _, ok := fieldsByName(rc.Table.Name, rc.Item)[column]; !ok {
+		rc.Inherited = append(rc.Inherited, column)
+	}
+	for _, cr := range rc.Children {
+		inherit(cr, column)
+	}
+}
+
+// Validate checks that every typed child in the tree rooted at rc accepts
+// the items of its parent, that every column of its table maps to either a
+// field or a parent key, and that no primary key column is nillable.
+func Validate(rc *Resource) error {
+	if rc.columnsErr != nil {
+		return fmt.Errorf("invalid columns for table %q: %w", rc.Table.Name, rc.columnsErr)
+	}
+	for _, c := range rc.Table.Columns {
+		if c.PrimaryKey && c.Nillable {
+			return fmt.Errorf("primary key column %q of table %q is nillable", c.Name, rc.Table.Name)
+		}
+	}
+	if rc.Item != nil {
+		if _, err := rc.columnIndex(); err != nil {
+			return fmt.Errorf("invalid columns for table %q: %w", rc.Table.Name, err)
+		}
+	}
+	if rc.Replace && !slices.ContainsFunc(rc.Table.Columns, func(c *v1.Column) bool { return c.PrimaryKey && slices.Contains(rc.Inherited, c.Name) }) {
+		return fmt.Errorf("table %q replaces the rows of each parent, but has no parent key in its primary key", rc.Table.Name)
+	}
+	for _, cr := range rc.Children {
+		if rc.Item != nil && cr.Parent != nil && !rc.Item.AssignableTo(cr.Parent) {
+			return fmt.Errorf("table %q expects parents of type %s, but is a child of table %q yielding %s", cr.Table.Name, cr.Parent, rc.Table.Name, rc.Item)
+		}
+		if err := Validate(cr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Of is a Resource whose resolver takes parents of type P and yields items of
+// type T. Its children must take parents of type T, which is checked at
+// compile time. The columns of Table are derived from T, with the primary
+// key columns named by PrimaryKey.
+type Of[P, T any] struct {
+	Table      *v1.Table
+	PrimaryKey []string
+	Resolver   func(context.Context, *meraki.Client, P) iter.Seq2[T, error]
+	Children   []Child[T]
+	Key        Key[T]
+	Replace    bool
+
+	once sync.Once
+	rc   *Resource
+}
+
+// Key names the column that the items of a resource contribute to the tables
+// of its descendants, e.g. network_id, and how to derive its value.
+type Key[T any] struct {
+	Column string
+	Value  func(T) string
+}
+
+// Child is a typed resource whose parents are of type P.
+type Child[P any] interface {
+	Resource() *Resource
+	childOf(P)
+}
+
+func (r *Of[P, T]) childOf(P) {}
+
+// Resource adapts r to the untyped resource tree. The same *Resource is
+// returned on every call.
+func (r *Of[P, T]) Resource() *Resource {
+	r.once.Do(func() {
+		rc := &Resource{
+			Table:   r.Table,
+			Parent:  reflect.TypeFor[P](),
+			Item:    reflect.TypeFor[T](),
+			Replace: r.Replace,
+		}
+		rc.Table.Columns, rc.columnsErr = columnsFor(rc.Table.Name, rc.Item, r.PrimaryKey)
+		rc.Resolver = func(ctx context.Context, client *meraki.Client, parent any) iter.Seq2[any, error] {
+			// the root resource is resolved without a parent
+			var p P
+			if parent != nil {
+				var ok bool
+				if p, ok = parent.(P); !ok {
+					return fail(fmt.Errorf("table %q expects parents of type %s, got %T", r.Table.Name, rc.Parent, parent))
+				}
+			}
+			return items(r.Resolver(ctx, client, p))
+		}
+		if r.Key.Column != "" {
+			rc.KeyColumn = r.Key.Column
+			rc.KeyValue = func(v any) string {
+				return r.Key.Value(v.(T))
+			}
+		}
+		for _, c := range r.Children {
+			cr := c.Resource()
+			if rc.KeyColumn != "" {
+				inherit(cr, rc.KeyColumn)
+			}
+			rc.Children = append(rc.Children, cr)
+		}
+		r.rc = rc
+	})
+	return r.rc
 }
@@ -18,30 +18,50 @@
 package resource
 
 import (
-	"bytes"
-	"errors"
-	"fmt"
+	"net/url"
+	"strings"
 	"unicode"
+
+	"github.com/go-resty/resty/v2"
 )
 
+// snakecase converts a Go identifier to snake case, keeping acronyms, also
+// in the plural, together as words: SSIDNumber is ssid_number and
+// AllowedVLANs is allowed_vlans.
 func snakecase(s string) string {
-	var out bytes.Buffer
-	caps := true
-	for i, r := range s {
-		if unicode.IsUpper(r) {
-			if i > 0 && !caps {
+	rs := []rune(s)
+	var out strings.Builder
+	for i, r := range rs {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := rs[i-1]
+			next := rune(0)
+			if i+1 < len(rs) {
+				next = rs[i+1]
+			}
+			// a word starts after a lowercase letter or digit, or at the
+			// last capital of an acronym followed by a lowercase word
+			if !unicode.IsUpper(prev) || unicode.IsLower(next) && !pluralAcronym(rs, i) && !sdkID(rs, i) {
 				out.WriteByte('_')
 			}
-			caps = true
-			r = unicode.ToLower(r)
-		} else {
-			caps = false
 		}
-		out.WriteRune(r)
+		out.WriteRune(unicode.ToLower(r))
 	}
 	return out.String()
 }
 
+// pluralAcronym reports whether the capital at i ends an acronym that is
+// followed by a plural s, as in VLANs.
+func pluralAcronym(rs []rune, i int) bool {
+	return i > 0 && unicode.IsUpper(rs[i-1]) && i+1 < len(rs) && rs[i+1] == 's' &&
+		(i+2 == len(rs) || unicode.IsUpper(rs[i+2]))
+}
+
+// sdkID reports whether the capital at i is the D of a word starting with
+// "Id" that the Meraki SDK spells with "ID", as in IDleTimeout or IDpID.
+func sdkID(rs []rune, i int) bool {
+	return rs[i] == 'D' && rs[i-1] == 'I' && (i == 1 || !unicode.IsUpper(rs[i-2]))
+}
+
 func ptr[T any](v T) *T {
 	return &v
 }
@@ -50,53 +70,24 @@ func zero[T any]() (t T) {
 	return
 }
 
-func box[T any](v any) (*T, error) {
-	if p, ok := v.(*T); ok {
-		return p, nil
-	} else if u, ok := v.(T); ok {
-		return &u, nil
+// startingAfter returns the startingAfter token of the next page from the
+// Link header of a paginated response.
+func startingAfter(rsp *resty.Response) (string, bool) {
+	if rsp == nil {
+		return "", false
 	}
-	return nil, fmt.Errorf("%+v is not (*)%T", v, zero[T]())
-}
-
-func boxWith[U, V any](conv func(U) V) func(any) (*V, error) {
-	return func(v any) (*V, error) {
-		if pu, ok := v.(*U); ok {
-			if pu == nil {
-				return nil, nil
-			}
-			return ptr(conv(*pu)), nil
-		} else if u, ok := v.(U); ok {
-			return ptr(conv(u)), nil
+	for _, link := range strings.Split(rsp.Header().Get("Link"), ",") {
+		target, params, ok := strings.Cut(link, ";")
+		if !ok || !strings.Contains(strings.ReplaceAll(params, `"`, ""), "rel=next") {
+			continue
 		}
-		return nil, fmt.Errorf("%+v is not (*)%T", v, zero[U]())
-	}
-}
-
-func tryAll[U, V any](u U, fs ...func(U) (V, error)) (V, error) {
-	errs := make([]error, len(fs))
-	for i, f := range fs {
-		if v, err := f(u); err == nil {
-			return v, nil
-		} else {
-			errs[i] = err
+		u, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+		if err != nil {
+			continue
+		}
+		if v := u.Query().Get("startingAfter"); v != "" {
+			return v, true
 		}
 	}
-	return zero[V](), errors.Join(errs...)
-}
-
-func converts[U, V any](us []U, conv func(U) V) []V {
-	vs := make([]V, len(us))
-	for i, u := range us {
-		vs[i] = conv(u)
-	}
-	return vs
+	return "", false
 }
-
-func ii32(i int) int32      { return int32(i) }
-func i8u8(i int8) uint8     { return uint8(i) }
-func i8i32(i int8) int32    { return int32(i) }
-func u8i32(i uint8) int32   { return int32(i) }
-func i16i32(i int16) int32  { return int32(i) }
-func u16i32(i uint16) int32 { return int32(i) }
-func u32i64(i uint32) int64 { return int64(i) }
@@ -0,0 +1,110 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package resource
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+)
+
+func TestStartingAfter(t *testing.T) {
+	const base = "https://api.meraki.com/api/v1/organizations/1/networks"
+	for _, tc := range []struct {
+		name string
+		link string
+		want string
+		ok   bool
+	}{
+		{name: "no header"},
+		{
+			name: "next",
+			link: `<` + base + `?perPage=1000&startingAfter=N_2>; rel=next`,
+			want: "N_2",
+			ok:   true,
+		},
+		{
+			name: "quoted rel",
+			link: `<` + base + `?startingAfter=N_2>; rel="next"`,
+			want: "N_2",
+			ok:   true,
+		},
+		{
+			name: "several links",
+			link: `<` + base + `?startingAfter=a>; rel=first, <` + base + `?endingBefore=N_1>; rel=prev, <` + base + `?startingAfter=N_3>; rel=next, <` + base + `?endingBefore=z>; rel=last`,
+			want: "N_3",
+			ok:   true,
+		},
+		{
+			name: "last page",
+			link: `<` + base + `?startingAfter=a>; rel=first, <` + base + `?endingBefore=z>; rel=last`,
+		},
+		{
+			name: "escaped token",
+			link: `<` + base + `?startingAfter=2024-01-01T00%3A00%3A00Z>; rel=next`,
+			want: "2024-01-01T00:00:00Z",
+			ok:   true,
+		},
+		{
+			name: "next without token",
+			link: `<` + base + `?perPage=1000>; rel=next`,
+		},
+		{
+			name: "invalid url",
+			link: `<%zz>; rel=next`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			header := http.Header{}
+			if tc.link != "" {
+				header.Set("Link", tc.link)
+			}
+			rsp := &resty.Response{RawResponse: &http.Response{Header: header}}
+			got, ok := startingAfter(rsp)
+			if got != tc.want || ok != tc.ok {
+				t.Errorf("startingAfter = %q, %t, want %q, %t", got, ok, tc.want, tc.ok)
+			}
+		})
+	}
+
+	if _, ok := startingAfter(nil); ok {
+		t.Error("startingAfter(nil) reported a next page")
+	}
+}
+
+func TestSnakecase(t *testing.T) {
+	for in, want := range map[string]string{
+		"ID":                 "id",
+		"NetworkID":          "network_id",
+		"LanIP":              "lan_ip",
+		"SSIDNumber":         "ssid_number",
+		"HasAPIKey":          "has_api_key",
+		"BeaconIDParams":     "beacon_id_params",
+		"AllowedVLANs":       "allowed_vlans",
+		"VLANsEnabled":       "vlans_enabled",
+		"IDleTimeoutMinutes": "idle_timeout_minutes",
+		"IDpID":              "idp_id",
+		"X509CertSha1":       "x509_cert_sha1",
+		"Dot3Az":             "dot3_az",
+	} {
+		if got := snakecase(in); got != want {
+			t.Errorf("snakecase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package resource
+
+import (
+	"context"
+	"iter"
+	"strconv"
+
+	"github.com/go-resty/resty/v2"
+	meraki "github.com/meraki/dashboard-api-go/v4/sdk"
+	v1 "github.com/secberus/go-push-api/types/v1"
+)
+
+var WirelessSSIDs = &Of[merakiNetwork, wirelessSSID]{
+	Table: &v1.Table{
+		Name:     "meraki_wireless_ssids",
+		SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
+	},
+	PrimaryKey: []string{"number"},
+	Resolver:   getNetworkWirelessSSIDs,
+	Key: Key[wirelessSSID]{
+		Column: "ssid_number",
+		Value:  func(s wirelessSSID) string { return s.number() },
+	},
+	Children: []Child[wirelessSSID]{
+		WirelessSSIDL3FirewallRules,
+		WirelessSSIDL7FirewallRules,
+		WirelessSSIDTrafficShaping,
+	},
+}
+
+// wirelessSSID is an SSID of a network, which the SSID endpoints are keyed by.
+type wirelessSSID struct {
+	NetworkID string                                             `s6s:"network_id,pk"`
+	SSID      meraki.ResponseItemWirelessGetNetworkWirelessSSIDs `s6s:",inline"`
+}
+
+func (s wirelessSSID) number() string {
+	if s.SSID.Number == nil {
+		return ""
+	}
+	return strconv.Itoa(*s.SSID.Number)
+}
+
+func getNetworkWirelessSSIDs(ctx context.Context, client *meraki.Client, network merakiNetwork) iter.Seq2[wirelessSSID, error] {
+	return func(yield func(wirelessSSID, error) bool) {
+		if lacksProductType("wireless")(network) {
+			return
+		}
+		ssids := list("GetNetworkWirelessSSIDs", func() (*meraki.ResponseWirelessGetNetworkWirelessSSIDs, *resty.Response, error) {
+			return client.Wireless.GetNetworkWirelessSSIDs(network.ID)
+		})
+		for ssid, err := range ssids {
+			if err != nil {
+				yield(wirelessSSID{}, err)
+				return
+			}
+			redact(&ssid)
+			if !yield(wirelessSSID{NetworkID: network.ID, SSID: ssid}, nil) {
+				return
+			}
+		}
+	}
+}
+
+var WirelessSSIDL3FirewallRules = ssidRules("meraki_wireless_ssid_l3_firewall_rules", "GetNetworkWirelessSSIDFirewallL3FirewallRules",
+	(*meraki.WirelessService).GetNetworkWirelessSSIDFirewallL3FirewallRules,
+	func(r *meraki.ResponseWirelessGetNetworkWirelessSSIDFirewallL3FirewallRules) *[]meraki.ResponseWirelessGetNetworkWirelessSSIDFirewallL3FirewallRulesRules {
+		return r.Rules
+	})
+
+var WirelessSSIDL7FirewallRules = ssidRules("meraki_wireless_ssid_l7_firewall_rules", "GetNetworkWirelessSSIDFirewallL7FirewallRules",
+	(*meraki.WirelessService).GetNetworkWirelessSSIDFirewallL7FirewallRules,
+	func(r *meraki.ResponseWirelessGetNetworkWirelessSSIDFirewallL7FirewallRules) *[]meraki.ResponseWirelessGetNetworkWirelessSSIDFirewallL7FirewallRulesRules {
+		return r.Rules
+	})
+
+// ssidRules returns a child of WirelessSSIDs with a row per rule.
+func ssidRules[R, E any](table, op string, fetch func(*meraki.WirelessService, string, string) (*R, *resty.Response, error), rules func(*R) *[]E) *Of[wirelessSSID, element[E]] {
+	return ruleList(table, op, nil, func(client *meraki.Client, s wirelessSSID) (*R, *resty.Response, error) {
+		return fetch(client.Wireless, s.NetworkID, s.number())
+	}, rules, "network_id", "ssid_number")
+}
+
+var WirelessSSIDTrafficShaping = &Of[wirelessSSID, meraki.ResponseWirelessGetNetworkWirelessSSIDTrafficShapingRules]{
+	Table: &v1.Table{
+		Name:     "meraki_wireless_ssid_traffic_shaping",
+		SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
+	},
+	PrimaryKey: []string{"network_id", "ssid_number"},
+	Resolver:   getNetworkWirelessSSIDTrafficShapingRules,
+}
+
+func getNetworkWirelessSSIDTrafficShapingRules(ctx context.Context, client *meraki.Client, ssid wirelessSSID) iter.Seq2[meraki.ResponseWirelessGetNetworkWirelessSSIDTrafficShapingRules, error] {
+	return single("GetNetworkWirelessSSIDTrafficShapingRules", func() (*meraki.ResponseWirelessGetNetworkWirelessSSIDTrafficShapingRules, *resty.Response, error) {
+		return client.Wireless.GetNetworkWirelessSSIDTrafficShapingRules(ssid.NetworkID, ssid.number())
+	})
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	v1 "github.com/secberus/go-push-api/types/v1"
+
+	"github.com/secberus/meraki-collector/resource"
+)
+
+const (
+	// SchemaPolicyRefuse fails registration of a table whose remote
+	// definition differs from the generated one.
+	SchemaPolicyRefuse = "refuse"
+	// SchemaPolicyIgnore logs the difference and uses the table as is,
+	// leaving the generated columns that it lacks or types differently out
+	// of records.
+	SchemaPolicyIgnore = "ignore"
+	// SchemaPolicyRecreate drops the remote table and creates it from the
+	// generated definition, discarding its records along with the collection
+	// state of the table, so that incremental resources collect from scratch.
+	SchemaPolicyRecreate = "recreate"
+)
+
+type columnChange struct {
+	remote *v1.Column
+	local  *v1.Column
+}
+
+// schemaDiff is the difference between the remote definition of a table and
+// the one generated by the collector. Columns that only differ in whether
+// they are nillable are not reported, so that tables created by earlier
+// versions of the collector still register.
+type schemaDiff struct {
+	table      string
+	added      []*v1.Column
+	removed    []*v1.Column
+	retyped    []columnChange
+	remotePKey []string
+	localPKey  []string
+}
+
+func diffTable(remote, local *v1.Table) *schemaDiff {
+	d := &schemaDiff{
+		table:      local.Name,
+		remotePKey: primaryKey(remote),
+		localPKey:  primaryKey(local),
+	}
+
+	rcs := make(map[string]*v1.Column, len(remote.Columns))
+	for _, c := range remote.Columns {
+		if c != nil {
+			rcs[c.Name] = c
+		}
+	}
+	for _, lc := range local.Columns {
+		if lc == nil {
+			continue
+		}
+		rc, ok := rcs[lc.Name]
+		if !ok {
+			d.added = append(d.added, lc)
+			continue
+		}
+		delete(rcs, lc.Name)
+		if resource.TypeName(rc.DataType) != resource.TypeName(lc.DataType) {
+			d.retyped = append(d.retyped, columnChange{remote: rc, local: lc})
+		}
+	}
+	for _, rc := range remote.Columns {
+		if rc != nil && rcs[rc.Name] != nil {
+			d.removed = append(d.removed, rc)
+		}
+	}
+	return d
+}
+
+func primaryKey(t *v1.Table) []string {
+	var pk []string
+	for _, c := range t.Columns {
+		if c != nil && c.PrimaryKey {
+			pk = append(pk, c.Name)
+		}
+	}
+	slices.Sort(pk)
+	return pk
+}
+
+// unsendable returns the names of the generated columns that the remote
+// table lacks or types differently.
+func (d *schemaDiff) unsendable() map[string]bool {
+	names := make(map[string]bool, len(d.added)+len(d.retyped))
+	for _, c := range d.added {
+		names[c.Name] = true
+	}
+	for _, c := range d.retyped {
+		names[c.local.Name] = true
+	}
+	return names
+}
+
+func (d *schemaDiff) Empty() bool {
+	return len(d.added) == 0 && len(d.removed) == 0 && len(d.retyped) == 0 && slices.Equal(d.remotePKey, d.localPKey)
+}
+
+func describeColumn(c *v1.Column) string {
+	s := c.Name + " " + resource.TypeName(c.DataType)
+	if !c.Nillable {
+		s += " not null"
+	}
+	return s
+}
+
+func (d *schemaDiff) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "table %q differs from its generated definition:", d.table)
+	for _, c := range d.added {
+		fmt.Fprintf(&b, "\n  + %s", describeColumn(c))
+	}
+	for _, c := range d.removed {
+		fmt.Fprintf(&b, "\n  - %s", describeColumn(c))
+	}
+	for _, c := range d.retyped {
+		fmt.Fprintf(&b, "\n  ~ %s -> %s", describeColumn(c.remote), describeColumn(c.local))
+	}
+	if !slices.Equal(d.remotePKey, d.localPKey) {
+		fmt.Fprintf(&b, "\n  ~ primary key (%s) -> (%s)", strings.Join(d.remotePKey, ", "), strings.Join(d.localPKey, ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/secberus/meraki-collector/config"
+	"github.com/secberus/meraki-collector/resource"
+)
+
+// job periodically collects a single resource of the tree.
+type job struct {
+	root     *resource.Resource
+	target   *resource.Resource
+	path     map[*resource.Resource]bool
+	interval time.Duration
+	jitter   time.Duration
+}
+
+// jobsFor creates one job per resource reachable from root, using the
+// table's configured schedule or the default interval.
+func jobsFor(root *resource.Resource, cfg *config.ServeConfig) ([]*job, error) {
+	var jobs []*job
+	seen := make(map[string]bool)
+
+	var walk func(rc *resource.Resource, ancestors []*resource.Resource) error
+	walk = func(rc *resource.Resource, ancestors []*resource.Resource) error {
+		if seen[rc.Table.Name] {
+			return fmt.Errorf("table %q appears more than once in the resource tree", rc.Table.Name)
+		}
+		seen[rc.Table.Name] = true
+
+		ancestors = append(ancestors, rc)
+		path := make(map[*resource.Resource]bool, len(ancestors))
+		for _, a := range ancestors {
+			path[a] = true
+		}
+
+		interval, ok := cfg.Schedules[rc.Table.Name]
+		if !ok {
+			interval = cfg.Interval
+		}
+		if interval <= 0 {
+			return fmt.Errorf("invalid interval %s for table %q", interval, rc.Table.Name)
+		}
+
+		jobs = append(jobs, &job{
+			root:     root,
+			target:   rc,
+			path:     path,
+			interval: interval,
+			jitter:   cfg.Jitter,
+		})
+		for _, cr := range rc.Children {
+			if err := walk(cr, ancestors[:len(ancestors):len(ancestors)]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(root, nil); err != nil {
+		return nil, err
+	}
+
+	for name := range cfg.Schedules {
+		if !seen[name] {
+			return nil, fmt.Errorf("schedule for unknown table %q", name)
+		}
+	}
+	return jobs, nil
+}
+
+func (j *job) delay() time.Duration {
+	if j.jitter <= 0 {
+		return 0
+	}
+	return rand.N(j.jitter)
+}
+
+// loop runs the job until ctx is cancelled. Runs never overlap: a run that
+// overruns its interval causes the missed runs to be skipped.
+func (j *job) loop(ctx context.Context, c *Collector) {
+	name := j.target.Table.Name
+	next := time.Now().Add(j.delay())
+
+	for {
+		select {
+		case <-time.After(time.Until(next)):
+		case <-ctx.Done():
+			return
+		}
+
+		start := time.Now()
+		log.Printf("starting scheduled collection for table %q", name)
+		r := &run{
+			failures: newFailures(c.maxFailures),
+			target:   j.target,
+			path:     j.path,
+		}
+		if err := c.run(ctx, r, j.root); err != nil {
+			log.Printf("scheduled collection for table %q failed: %s", name, err)
+		} else {
+			log.Printf("finished scheduled collection for table %q in %s", name, time.Since(start))
+		}
+
+		next = start.Add(j.interval)
+		skipped := 0
+		for time.Now().After(next) {
+			next = next.Add(j.interval)
+			skipped++
+		}
+		if skipped > 0 {
+			log.Printf("collection for table %q overran its %s interval, skipping %d runs", name, j.interval, skipped)
+		}
+		next = next.Add(j.delay())
+	}
+}
+
+// Serve collects every resource reachable from root on its own schedule
+// until ctx is cancelled, then waits for in-flight collections to finish.
+func (c *Collector) Serve(ctx context.Context, root *resource.Resource, cfg *config.ServeConfig) error {
+	if err := resource.Validate(root); err != nil {
+		return fmt.Errorf("invalid resource tree: %w", err)
+	}
+
+	jobs, err := jobsFor(root, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to schedule collection: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, j := range jobs {
+		log.Printf("scheduling table %q every %s", j.target.Table.Name, j.interval)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			j.loop(ctx, c)
+		}()
+	}
+
+	<-ctx.Done()
+	log.Printf("shutting down, waiting for in-flight collections")
+	wg.Wait()
+	return nil
+}
@@ -0,0 +1,191 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	api "github.com/secberus/go-push-api/api/v1"
+	service "github.com/secberus/go-push-api/service/v1/push"
+	v1 "github.com/secberus/go-push-api/types/v1"
+
+	"github.com/secberus/meraki-collector/config"
+	"github.com/secberus/meraki-collector/state"
+)
+
+// Sink is the destination of the tables and records produced by the
+// Collector. Register is called once per table before any of its records
+// are upserted.
+type Sink interface {
+	Register(ctx context.Context, t *v1.Table) error
+	Upsert(ctx context.Context, recs []*v1.Record) error
+	Close() error
+}
+
+// encodeErrorSink is implemented by sinks that account for records that
+// fail to encode rather than failing the branch they belong to.
+type encodeErrorSink interface {
+	EncodeError(t *v1.Table, err error)
+}
+
+// deleteSink is implemented by sinks that can delete records, which the
+// tables of resources that replace their rows depend on. Records are matched
+// by the columns given, which may be a subset of the primary key, e.g. the
+// parent key columns of the rows of a parent. Sinks that cannot delete leave
+// replaced rows in place.
+type deleteSink interface {
+	Delete(ctx context.Context, keys []*v1.Record) error
+}
+
+func initSink(cfg *config.Config, store state.Store) (Sink, error) {
+	switch cfg.Sink.Type {
+	case "", "push":
+		pushsvc, err := initPushClient(&cfg.S6s)
+		if err != nil {
+			return nil, err
+		}
+		switch cfg.S6s.SchemaPolicy {
+		case SchemaPolicyRefuse, SchemaPolicyIgnore, SchemaPolicyRecreate:
+		default:
+			return nil, fmt.Errorf("unknown schema_policy %q", cfg.S6s.SchemaPolicy)
+		}
+		return &pushSink{
+			pushsvc: pushsvc,
+			policy:  cfg.S6s.SchemaPolicy,
+			state:   store,
+			omit:    make(map[string]map[string]bool),
+		}, nil
+	case "file":
+		return newFileSink(cfg.Sink.Path)
+	default:
+		return nil, fmt.Errorf("unknown sink type %q", cfg.Sink.Type)
+	}
+}
+
+// pushSink sends tables and records to the Secberus Push API.
+type pushSink struct {
+	pushsvc service.PushServiceClient
+	policy  string
+	state   state.Store
+
+	// omit holds the columns left out of the records of each table that is
+	// registered as is under SchemaPolicyIgnore
+	mu   sync.Mutex
+	omit map[string]map[string]bool
+}
+
+func (s *pushSink) Register(ctx context.Context, t *v1.Table) error {
+	out, err := s.pushsvc.GetTable(ctx, &api.GetTableInput{TableName: t.Name})
+	if err != nil && strings.Contains(err.Error(), "not found") {
+		log.Printf("table %q does not exist, creating\n", t.Name)
+		return s.create(ctx, t)
+	} else if err != nil {
+		return fmt.Errorf("failed to GetTable: %w", err)
+	}
+
+	if out.GetTable() == nil {
+		log.Printf("no definition returned for table %q, skipping schema check\n", t.Name)
+		return nil
+	}
+	d := diffTable(out.GetTable(), t)
+	if d.Empty() {
+		return nil
+	}
+	log.Println(d)
+
+	switch s.policy {
+	case SchemaPolicyIgnore:
+		if omit := d.unsendable(); len(omit) > 0 {
+			log.Printf("leaving %s out of the records of table %q\n", strings.Join(slices.Sorted(maps.Keys(omit)), ", "), t.Name)
+			s.mu.Lock()
+			s.omit[t.Name] = omit
+			s.mu.Unlock()
+		}
+		return nil
+	case SchemaPolicyRecreate:
+		log.Printf("recreating table %q\n", t.Name)
+		// the state is cleared first, as a checkpoint that outlives the
+		// records it describes would skip them for good
+		if err := s.state.Clear(ctx, t.Name); err != nil {
+			return fmt.Errorf("failed to clear state of table %q: %w", t.Name, err)
+		}
+		if _, err := s.pushsvc.DropTable(ctx, &api.DropTableInput{TableName: t.Name}); err != nil {
+			return fmt.Errorf("failed to DropTable: %w", err)
+		}
+		return s.create(ctx, t)
+	default:
+		return fmt.Errorf("refusing to migrate table %q with schema_policy %q", t.Name, s.policy)
+	}
+}
+
+func (s *pushSink) create(ctx context.Context, t *v1.Table) error {
+	if _, err := s.pushsvc.CreateTable(ctx, &api.CreateTableInput{Table: t}); err != nil {
+		return fmt.Errorf("failed to CreateTable: %w", err)
+	}
+	return nil
+}
+
+func (s *pushSink) Upsert(ctx context.Context, recs []*v1.Record) error {
+	recs = s.omitColumns(recs)
+	if _, err := s.pushsvc.UpsertRecords(ctx, &api.UpsertRecordsInput{Records: recs}); err != nil {
+		return fmt.Errorf("failed to UpsertRecords: %w", err)
+	}
+	return nil
+}
+
+// omitColumns returns recs without the columns that their tables omit.
+func (s *pushSink) omitColumns(recs []*v1.Record) []*v1.Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.omit) == 0 {
+		return recs
+	}
+
+	out := make([]*v1.Record, len(recs))
+	for i, r := range recs {
+		omit := s.omit[r.TableName]
+		if omit == nil {
+			out[i] = r
+			continue
+		}
+		out[i] = &v1.Record{
+			TableName: r.TableName,
+			Columns: slices.DeleteFunc(slices.Clone(r.Columns), func(c *v1.Column) bool {
+				return omit[c.Name]
+			}),
+		}
+	}
+	return out
+}
+
+func (s *pushSink) Delete(ctx context.Context, keys []*v1.Record) error {
+	if _, err := s.pushsvc.DeleteRecords(ctx, &api.DeleteRecordsInput{PrimaryKey: keys}); err != nil {
+		return fmt.Errorf("failed to DeleteRecords: %w", err)
+	}
+	return nil
+}
+
+func (s *pushSink) Close() error {
+	return nil
+}
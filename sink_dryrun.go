@@ -0,0 +1,140 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"text/tabwriter"
+
+	v1 "github.com/secberus/go-push-api/types/v1"
+
+	"github.com/secberus/meraki-collector/resource"
+)
+
+// maxReportedErrors is the number of encoding errors listed per table.
+const maxReportedErrors = 5
+
+// dryRunSink discards records, keeping only the statistics needed to
+// validate resources and schema mapping.
+type dryRunSink struct {
+	mu     sync.Mutex
+	tables map[string]*tableStats
+}
+
+type tableStats struct {
+	table   *v1.Table
+	records int
+	nulls   map[string]int
+	errors  int
+	samples []string
+}
+
+func newDryRunSink() *dryRunSink {
+	return &dryRunSink{tables: make(map[string]*tableStats)}
+}
+
+func (s *dryRunSink) stats(t *v1.Table) *tableStats {
+	ts, ok := s.tables[t.Name]
+	if !ok {
+		ts = &tableStats{table: t, nulls: make(map[string]int)}
+		s.tables[t.Name] = ts
+	}
+	return ts
+}
+
+func (s *dryRunSink) Register(_ context.Context, t *v1.Table) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats(t)
+	return nil
+}
+
+func (s *dryRunSink) Upsert(_ context.Context, recs []*v1.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range recs {
+		ts, ok := s.tables[r.TableName]
+		if !ok {
+			return fmt.Errorf("table %q is not registered", r.TableName)
+		}
+		ts.records++
+		for _, c := range r.Columns {
+			if c != nil && columnValue(c.DataType) == nil {
+				ts.nulls[c.Name]++
+			}
+		}
+	}
+	return nil
+}
+
+// EncodeError counts a record of t that could not be encoded, instead of
+// failing the branch it belongs to.
+func (s *dryRunSink) EncodeError(t *v1.Table, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ts := s.stats(t)
+	ts.errors++
+	if len(ts.samples) < maxReportedErrors {
+		ts.samples = append(ts.samples, err.Error())
+	}
+}
+
+func (s *dryRunSink) Close() error {
+	return nil
+}
+
+// Report writes the record counts, column null rates, encoding errors and
+// coercion failures of every table.
+func (s *dryRunSink) Report(w io.Writer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.tables))
+	for name := range s.tables {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	coercions := resource.CoercionFailures()
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, name := range names {
+		ts := s.tables[name]
+		fmt.Fprintf(tw, "%s\t%d records\t%d encoding errors\t%d coercion failures\n", name, ts.records, ts.errors, coercions[name])
+		for _, c := range ts.table.Columns {
+			if c == nil {
+				continue
+			}
+			rate := 0.0
+			if ts.records > 0 {
+				rate = 100 * float64(ts.nulls[c.Name]) / float64(ts.records)
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%.1f%% null\n", c.Name, resource.TypeName(c.DataType), rate)
+		}
+		for _, e := range ts.samples {
+			fmt.Fprintf(tw, "  error: %s\n", e)
+		}
+	}
+	return tw.Flush()
+}
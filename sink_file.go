@@ -0,0 +1,195 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	v1 "github.com/secberus/go-push-api/types/v1"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/secberus/meraki-collector/resource"
+)
+
+// fileSink writes the records of each table as NDJSON to <dir>/<table>.ndjson,
+// alongside the table's schema in <dir>/<table>.schema.json. Records are
+// appended, so a file accumulates the records of every run.
+type fileSink struct {
+	dir string
+
+	mu    sync.Mutex
+	files map[string]*ndjsonFile
+}
+
+type ndjsonFile struct {
+	mu sync.Mutex
+	f  *os.File
+	w  *bufio.Writer
+}
+
+func newFileSink(dir string) (*fileSink, error) {
+	if dir == "" {
+		return nil, errors.New("file sink requires a path")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &fileSink{dir: dir, files: make(map[string]*ndjsonFile)}, nil
+}
+
+type schemaColumn struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	PrimaryKey bool   `json:"primary_key,omitempty"`
+	Nillable   bool   `json:"nillable,omitempty"`
+	Unique     bool   `json:"unique,omitempty"`
+}
+
+type schema struct {
+	Name     string         `json:"name"`
+	SyncType string         `json:"sync_type"`
+	Columns  []schemaColumn `json:"columns"`
+}
+
+func (s *fileSink) Register(_ context.Context, t *v1.Table) error {
+	sc := schema{Name: t.Name, SyncType: t.SyncType.String()}
+	for _, c := range t.Columns {
+		if c == nil {
+			continue
+		}
+		sc.Columns = append(sc.Columns, schemaColumn{
+			Name:       c.Name,
+			Type:       resource.TypeName(c.DataType),
+			PrimaryKey: c.PrimaryKey,
+			Nillable:   c.Nillable,
+			Unique:     c.Unique,
+		})
+	}
+	raw, err := json.MarshalIndent(sc, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(s.dir, t.Name+".schema.json"), raw, 0o644); err != nil {
+		return fmt.Errorf("failed to write schema: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.files[t.Name]; ok {
+		return nil
+	}
+	f, err := os.OpenFile(filepath.Join(s.dir, t.Name+".ndjson"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open records file: %w", err)
+	}
+	s.files[t.Name] = &ndjsonFile{f: f, w: bufio.NewWriter(f)}
+	return nil
+}
+
+func (s *fileSink) Upsert(_ context.Context, recs []*v1.Record) error {
+	touched := make(map[*ndjsonFile]string)
+	for _, r := range recs {
+		s.mu.Lock()
+		nf, ok := s.files[r.TableName]
+		s.mu.Unlock()
+		if !ok {
+			return fmt.Errorf("table %q is not registered", r.TableName)
+		}
+
+		raw, err := json.Marshal(recordValues(r))
+		if err != nil {
+			return fmt.Errorf("failed to encode record for table %q: %w", r.TableName, err)
+		}
+
+		nf.mu.Lock()
+		nf.w.Write(raw)
+		err = nf.w.WriteByte('\n')
+		nf.mu.Unlock()
+		if err != nil {
+			return fmt.Errorf("failed to write record for table %q: %w", r.TableName, err)
+		}
+		touched[nf] = r.TableName
+	}
+
+	for nf, name := range touched {
+		nf.mu.Lock()
+		err := nf.w.Flush()
+		nf.mu.Unlock()
+		if err != nil {
+			return fmt.Errorf("failed to flush records for table %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	for _, nf := range s.files {
+		errs = append(errs, nf.w.Flush(), nf.f.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// recordValues maps each column of r to its value, or nil if it is unset.
+// jsonb values are embedded as JSON rather than as strings.
+func recordValues(r *v1.Record) map[string]any {
+	values := make(map[string]any, len(r.Columns))
+	for _, c := range r.Columns {
+		if c == nil {
+			continue
+		}
+		values[c.Name] = columnValue(c.DataType)
+	}
+	return values
+}
+
+func columnValue(dt *v1.DataType) any {
+	if dt == nil {
+		return nil
+	}
+	m := dt.ProtoReflect()
+	fd := m.WhichOneof(m.Descriptor().Oneofs().ByName("union"))
+	if fd == nil {
+		return nil
+	}
+	um := m.Get(fd).Message()
+	vfd := um.Descriptor().Fields().ByName("value")
+	if vfd == nil || !um.Has(vfd) {
+		return nil
+	}
+	v := um.Get(vfd)
+	if jt := dt.GetJsonb(); jt != nil {
+		return json.RawMessage(jt.GetValue())
+	}
+	switch vfd.Kind() {
+	case protoreflect.BytesKind:
+		return v.Bytes()
+	default:
+		return v.Interface()
+	}
+}
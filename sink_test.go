@@ -0,0 +1,169 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	meraki "github.com/meraki/dashboard-api-go/v4/sdk"
+	api "github.com/secberus/go-push-api/api/v1"
+	service "github.com/secberus/go-push-api/service/v1/push"
+	v1 "github.com/secberus/go-push-api/types/v1"
+	"google.golang.org/grpc"
+
+	"github.com/secberus/meraki-collector/config"
+	"github.com/secberus/meraki-collector/resource"
+	"github.com/secberus/meraki-collector/state"
+)
+
+// fakePush records the deletes and upserts sent to the Push API. Tables are
+// reported as existing without a definition, which skips the schema check.
+type fakePush struct {
+	service.PushServiceClient
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (f *fakePush) GetTable(context.Context, *api.GetTableInput, ...grpc.CallOption) (*api.GetTableOutput, error) {
+	return &api.GetTableOutput{}, nil
+}
+
+func (f *fakePush) UpsertRecords(_ context.Context, in *api.UpsertRecordsInput, _ ...grpc.CallOption) (*api.UpsertRecordsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, fmt.Sprintf("upsert %s %d", in.Records[0].TableName, len(in.Records)))
+	return &api.UpsertRecordsOutput{}, nil
+}
+
+func (f *fakePush) DeleteRecords(_ context.Context, in *api.DeleteRecordsInput, _ ...grpc.CallOption) (*api.DeleteRecordsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, k := range in.PrimaryKey {
+		var cols []string
+		for _, c := range k.Columns {
+			cols = append(cols, c.Name+"="+c.DataType.GetText().GetValue())
+		}
+		f.calls = append(f.calls, fmt.Sprintf("delete %s %s", k.TableName, strings.Join(cols, ",")))
+	}
+	return &api.DeleteRecordsOutput{}, nil
+}
+
+type replaceParent struct {
+	ID string `s6s:"id,pk"`
+}
+
+type replaceRow struct {
+	Ordinal int `s6s:"ordinal,pk"`
+}
+
+// replaceResource yields a single parent with n rows, failing after the
+// rows if fail is set.
+func replaceResource(n int, fail bool) *resource.Resource {
+	rows := &resource.Of[replaceParent, replaceRow]{
+		Table: &v1.Table{
+			Name:     "test_replace_rows",
+			SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
+		},
+		PrimaryKey: []string{"parent_id"},
+		Replace:    true,
+		Resolver: func(context.Context, *meraki.Client, replaceParent) iter.Seq2[replaceRow, error] {
+			return func(yield func(replaceRow, error) bool) {
+				for i := range n {
+					if !yield(replaceRow{Ordinal: i}, nil) {
+						return
+					}
+				}
+				if fail {
+					yield(replaceRow{}, errors.New("500 Internal Server Error"))
+				}
+			}
+		},
+	}
+	return (&resource.Of[any, replaceParent]{
+		Table: &v1.Table{
+			Name:     "test_replace_parents",
+			SyncType: v1.TableSyncType_TABLE_SYNC_TYPE_APPEND,
+		},
+		Resolver: func(context.Context, *meraki.Client, any) iter.Seq2[replaceParent, error] {
+			return func(yield func(replaceParent, error) bool) {
+				yield(replaceParent{ID: "p1"}, nil)
+			}
+		},
+		Key: resource.Key[replaceParent]{
+			Column: "parent_id",
+			Value:  func(p replaceParent) string { return p.ID },
+		},
+		Children: []resource.Child[replaceParent]{rows},
+	}).Resource()
+}
+
+func TestCollectReplace(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		fail  bool
+		calls []string
+	}{
+		{
+			name: "complete",
+			calls: []string{
+				"delete test_replace_rows parent_id=p1",
+				"upsert test_replace_rows 2",
+				"upsert test_replace_rows 2",
+				"upsert test_replace_rows 1",
+			},
+		},
+		{
+			name: "failed",
+			fail: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			push := &fakePush{}
+			sink := &pushSink{
+				pushsvc: push,
+				policy:  SchemaPolicyRefuse,
+				state:   state.NewMemory(),
+				omit:    make(map[string]map[string]bool),
+			}
+			c := NewCollector(nil, sink, state.NewMemory(), &config.CollectorConfig{
+				BatchSize:   2,
+				BatchBytes:  1 << 20,
+				MaxFailures: -1,
+			})
+			err := c.Collect(context.Background(), replaceResource(5, tc.fail))
+			if !tc.fail && err != nil {
+				t.Fatalf("Collect: %v", err)
+			}
+
+			calls := slices.DeleteFunc(push.calls, func(c string) bool {
+				return !strings.Contains(c, "test_replace_rows")
+			})
+			if !slices.Equal(calls, tc.calls) {
+				t.Errorf("calls = %q, want %q", calls, tc.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// DirStore keeps one JSON file per entry below a local directory, laid out
+// as <dir>/<table>/<parent>.json.
+type DirStore struct {
+	dir string
+}
+
+func OpenDir(dir string) (*DirStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &DirStore{dir: dir}, nil
+}
+
+func (s *DirStore) path(key Key) string {
+	parent := key.Parent
+	if parent == "" {
+		parent = "_"
+	}
+	return filepath.Join(s.dir, url.PathEscape(key.Table), url.PathEscape(parent)+".json")
+}
+
+func (s *DirStore) Load(_ context.Context, key Key) (Entry, bool, error) {
+	var e Entry
+	raw, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return e, false, nil
+	} else if err != nil {
+		return e, false, err
+	}
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return e, false, fmt.Errorf("failed to parse state file: %w", err)
+	}
+	return e, true, nil
+}
+
+func (s *DirStore) Save(_ context.Context, key Key, e Entry) error {
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	// write to a temporary file first so that a crash never leaves a
+	// truncated entry behind
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *DirStore) Clear(_ context.Context, table string) error {
+	return os.RemoveAll(filepath.Join(s.dir, url.PathEscape(table)))
+}
+
+func (s *DirStore) Close() error {
+	return nil
+}
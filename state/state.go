@@ -0,0 +1,151 @@
+/*
+ * Copyright 2025 Secberus, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package state
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Key identifies the state of a single resolver invocation.
+type Key struct {
+	Table  string
+	Parent string
+}
+
+func (k Key) String() string {
+	return k.Table + "/" + k.Parent
+}
+
+// Entry is the state persisted for a Key.
+type Entry struct {
+	Cursor  string    `json:"cursor,omitempty"`
+	ETag    string    `json:"etag,omitempty"`
+	LastRun time.Time `json:"last_run,omitzero"`
+}
+
+// Store persists entries between runs.
+type Store interface {
+	Load(ctx context.Context, key Key) (Entry, bool, error)
+	Save(ctx context.Context, key Key, e Entry) error
+	// Clear deletes the entries of every parent of table.
+	Clear(ctx context.Context, table string) error
+	Close() error
+}
+
+// Scope stages changes to the entry of a single Key until Commit. Only
+// scopes that were read or written are saved, so resolvers that do not use
+// state cost nothing.
+type Scope struct {
+	mu    sync.Mutex
+	store Store
+	key   Key
+	entry Entry
+	used  bool
+}
+
+func NewScope(ctx context.Context, store Store, key Key) (*Scope, error) {
+	e, _, err := store.Load(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state for %q: %w", key, err)
+	}
+	return &Scope{store: store, key: key, entry: e}, nil
+}
+
+func (sc *Scope) Cursor() string {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.used = true
+	return sc.entry.Cursor
+}
+
+func (sc *Scope) SetCursor(v string) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.used = true
+	sc.entry.Cursor = v
+}
+
+func (sc *Scope) ETag() string {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.used = true
+	return sc.entry.ETag
+}
+
+func (sc *Scope) SetETag(v string) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.used = true
+	sc.entry.ETag = v
+}
+
+func (sc *Scope) LastRun() time.Time {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.used = true
+	return sc.entry.LastRun
+}
+
+// Commit saves the staged entry, recording start as the time of the last
+// successful run.
+func (sc *Scope) Commit(ctx context.Context, start time.Time) error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if !sc.used {
+		return nil
+	}
+	sc.entry.LastRun = start
+	if err := sc.store.Save(ctx, sc.key, sc.entry); err != nil {
+		return fmt.Errorf("failed to save state for %q: %w", sc.key, err)
+	}
+	return nil
+}
+
+// Open returns the Store for the given driver: "dir" (the default) stores
+// entries below path, "memory" keeps them for the lifetime of the process.
+func Open(driver, path string) (Store, error) {
+	switch driver {
+	case "", "dir":
+		return OpenDir(path)
+	case "memory":
+		return NewMemory(), nil
+	default:
+		return nil, fmt.Errorf("unknown state driver %q", driver)
+	}
+}
+
+// ReadOnly returns a Store that loads entries from s but discards saves.
+func ReadOnly(s Store) Store {
+	return readOnly{s}
+}
+
+type readOnly struct {
+	Store
+}
+
+func (readOnly) Save(context.Context, Key, Entry) error {
+	return nil
+}
+
+func (readOnly) Clear(context.Context, string) error {
+	return nil
+}